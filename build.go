@@ -0,0 +1,433 @@
+package rbtree
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// minParallelBuild is the smallest amount of values for which a subtree is built in a separate goroutine.
+const minParallelBuild = 1 << 12
+
+// FromSortedSlice builds a balanced red-black tree from the values sorted in strictly ascending order by cmp in O(n) time.
+// The middle value of the slice becomes the root and the halves become its subtrees recursively.
+// The values are not checked: if they are not in strictly ascending order, the tree is not valid.
+// To check the values, use ResetFromSorted on an empty tree.
+func FromSortedSlice[T any](vals []T, cmp func(T, T) int) *RBTree[T] {
+	return BuildFromSortedParallel(vals, cmp, 1)
+}
+
+// BuildFromSortedParallel builds a red-black tree from the values sorted in strictly ascending order by cmp.
+// The slice is split into up to workers consecutive chunks, which are built into balanced trees concurrently,
+// and the trees are combined with Join in O(workers log n) time. If workers <= 1 or the slice is too small to split,
+// the tree is built sequentially. Either way building takes O(n) work.
+// The values are not checked: if they are not in strictly ascending order, the tree is not valid.
+func BuildFromSortedParallel[T any](sorted []T, cmp func(T, T) int, workers int) *RBTree[T] {
+	chunks := min(workers, len(sorted)/minParallelBuild)
+
+	if chunks <= 1 {
+		rbt := New(cmp)
+		rbt.setRoot(buildSorted(sorted, nil, 0, redDepth(len(sorted))), len(sorted))
+
+		return rbt
+	}
+
+	trees := make([]*RBTree[T], chunks)
+
+	var wg sync.WaitGroup
+
+	for i := range chunks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			chunk := sorted[i*len(sorted)/chunks : (i+1)*len(sorted)/chunks]
+			trees[i] = New(cmp)
+			trees[i].setRoot(buildSorted(chunk, nil, 0, redDepth(len(chunk))), len(chunk))
+		}()
+	}
+
+	wg.Wait()
+
+	for _, tree := range trees[1:] {
+		trees[0].join(tree)
+	}
+
+	return trees[0]
+}
+
+// ResetFromSorted replaces the values of the tree with the values sorted in strictly ascending order.
+// The tree is rebuilt in O(n) time, keeping its comparison function and options. An empty slice clears the tree.
+// ResetFromSorted returns [ErrNotSorted] if the values are not in strictly ascending order,
+// or [ErrFrozen] if the tree is frozen. In case of an error the tree is left unchanged.
+func (rbt *RBTree[T]) ResetFromSorted(sorted []T) error {
+	if rbt.frozen {
+		return ErrFrozen
+	}
+
+	for i := 1; i < len(sorted); i++ {
+		if rbt.cmp(sorted[i-1], sorted[i]) >= 0 {
+			return ErrNotSorted
+		}
+	}
+
+	tree := rbt.emptyCopy()
+	tree.setRoot(buildSorted(sorted, nil, 0, redDepth(len(sorted))), len(sorted))
+
+	*rbt = *tree
+
+	return nil
+}
+
+// Split returns two new trees with the same options: the first one contains the values smaller than val,
+// and the second one contains the values bigger than or equal to val. The tree is left unchanged.
+// Both trees are built from sorted values like FromSortedSlice, so Split takes O(n) time.
+func (rbt *RBTree[T]) Split(val T) (*RBTree[T], *RBTree[T]) {
+	var smaller, rest []*RBNode[T]
+
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		if rbt.cmp(rbn.Val, val) < 0 {
+			smaller = append(smaller, rbn)
+		} else {
+			rest = append(rest, rbn)
+		}
+	}
+
+	return rbt.copySorted(smaller), rbt.copySorted(rest)
+}
+
+// Join moves all values of the other tree, which must be bigger than all values of the tree, into the tree.
+// The smallest node of the other tree is detached and becomes the pivot, which links the trees at the level
+// of the same black height, so Join takes O(log n) time. The other tree must be created with the same comparison
+// function and options; it is left empty. If the insertion order is tracked, the values of the other tree
+// are considered newer. Join returns [ErrNotSorted] if the ranges of the trees overlap,
+// or [ErrFrozen] if either tree is frozen. In case of an error both trees are left unchanged.
+func (rbt *RBTree[T]) Join(other *RBTree[T]) error {
+	if rbt.frozen || other != nil && other.frozen {
+		return ErrFrozen
+	}
+
+	if other == nil || other.root == nil {
+		return nil
+	}
+
+	if rbt.root != nil && rbt.cmp(rbt.Max.Val, other.Min.Val) >= 0 {
+		return ErrNotSorted
+	}
+
+	rbt.join(other)
+
+	return nil
+}
+
+// join moves all values of the non-empty other tree, which are not checked to be bigger than all values of the tree,
+// into the tree like Join.
+func (rbt *RBTree[T]) join(other *RBTree[T]) {
+	count := rbt.Count + other.Count
+	otherOldest, otherNewest := other.oldest, other.newest
+	pivot := other.detachMin()
+
+	if rbt.root == nil {
+		rbt.Min = pivot
+	}
+
+	if other.root != nil {
+		rbt.Max = other.Max
+	} else {
+		rbt.Max = pivot
+	}
+
+	rbt.link(pivot, other.root)
+	rbt.Count = count
+
+	switch {
+	case !rbt.insertionOrder:
+	case other.insertionOrder && otherOldest != nil:
+		otherOldest.older = rbt.newest
+
+		if rbt.newest != nil {
+			rbt.newest.newer = otherOldest
+		} else {
+			rbt.oldest = otherOldest
+		}
+
+		rbt.newest = otherNewest
+	default:
+		for rbn := pivot; rbn != nil; rbn = rbn.nextNode() {
+			rbt.pushNewest(rbn)
+		}
+	}
+
+	other.root, other.Min, other.Max = nil, nil, nil
+	other.oldest, other.newest = nil, nil
+	other.Count = 0
+}
+
+// detachMin removes the Min node from the tree, keeping its value and its links in the insertion order list,
+// and returns it. Min and the augmented data are updated, but Count is left unchanged.
+func (rbt *RBTree[T]) detachMin() *RBNode[T] {
+	rbn := rbt.Min
+	parent := rbn.parent
+	rbt.Min = rbn.nextNode()
+
+	switch {
+	case rbn.right != nil: // the only child of the leftmost node is a red leaf
+		rbn.right.parent = parent
+		rbt.paint(rbn.right, true)
+
+		if parent == nil {
+			rbt.root = rbn.right
+		} else {
+			parent.left = rbn.right
+		}
+	case parent == nil:
+		rbt.root, rbt.Min, rbt.Max = nil, nil, nil
+	default:
+		rbt.deleteNoChildren(rbn)
+	}
+
+	rbt.updatePath(parent)
+	rbn.left, rbn.right, rbn.parent = nil, nil, nil
+
+	return rbn
+}
+
+// link joins the tree, the pivot node and the right subtree, whose values are bigger than the pivot,
+// which in turn is bigger than all values of the tree. The pivot is linked as a red node in place of the node
+// on the right spine of the taller tree (or the left spine of the right subtree) with the black height
+// of the shorter one, and the double red is fixed like after an insertion.
+func (rbt *RBTree[T]) link(pivot, right *RBNode[T]) {
+	leftHeight, rightHeight := rbt.root.blackHeight(), right.blackHeight()
+	pivot.isBlack = false
+
+	var parent *RBNode[T]
+
+	if leftHeight >= rightHeight {
+		cur := rbt.root
+
+		for ; cur != nil && (!cur.isBlack || leftHeight > rightHeight); cur = cur.right {
+			if cur.isBlack {
+				leftHeight--
+			}
+
+			parent = cur
+		}
+
+		pivot.left, pivot.right = cur, right
+
+		if parent == nil {
+			rbt.root = pivot
+		} else {
+			parent.right = pivot
+		}
+	} else {
+		cur := right
+
+		for ; cur != nil && (!cur.isBlack || rightHeight > leftHeight); cur = cur.left {
+			if cur.isBlack {
+				rightHeight--
+			}
+
+			parent = cur
+		}
+
+		pivot.left, pivot.right = rbt.root, cur
+		rbt.root = right
+		parent.left = pivot
+	}
+
+	pivot.parent = parent
+
+	for _, child := range []*RBNode[T]{pivot.left, pivot.right} {
+		if child != nil {
+			child.parent = pivot
+		}
+	}
+
+	rbt.updatePath(pivot)
+
+	switch {
+	case parent == nil:
+		rbt.paint(pivot, true)
+	case !parent.isBlack:
+		rbt.solveDoubleRed(parent)
+	}
+}
+
+// copySorted returns a new balanced tree with the same options, which contains the values of the nodes
+// sorted in strictly ascending order together with their multiplicities.
+func (rbt *RBTree[T]) copySorted(nodes []*RBNode[T]) *RBTree[T] {
+	vals := make([]T, len(nodes))
+	for i, rbn := range nodes {
+		vals[i] = rbn.Val
+	}
+
+	tree := rbt.emptyCopy()
+	root := buildSorted(vals, nil, 0, redDepth(len(vals)))
+	count := len(vals)
+
+	if rbt.multiset && root != nil {
+		for i, rbn := 0, root.leftmost(); rbn != nil; i, rbn = i+1, rbn.nextNode() {
+			rbn.dups = nodes[i].dups
+			count += rbn.dups
+		}
+	}
+
+	tree.setRoot(root, count)
+
+	return tree
+}
+
+// setRoot makes the subtree of count nodes the contents of the empty tree.
+// The augmented data is computed, and the values are added to the insertion order in ascending order if it is tracked.
+func (rbt *RBTree[T]) setRoot(root *RBNode[T], count int) {
+	if root == nil {
+		return
+	}
+
+	rbt.root = root
+	rbt.Min = root.leftmost()
+	rbt.Max = root.rightmost()
+	rbt.Count = count
+	rbt.updateSubtree(root)
+
+	for rbn := rbt.Min; rbt.insertionOrder && rbn != nil; rbn = rbn.nextNode() {
+		rbt.pushNewest(rbn)
+	}
+}
+
+// redDepth returns the depth of the deepest nodes of a tree of count nodes built by buildSorted.
+func redDepth(count int) int {
+	return bits.Len(uint(count)) - 1
+}
+
+// buildSorted links the sorted values into a balanced subtree, which root is at the given depth, and returns the root.
+// The nodes at redDepth (the depth of the deepest nodes, see redDepth) are red, unless it is the root, and the other nodes are black.
+// All levels above redDepth are full, so all paths of the tree contain the same number of black nodes.
+func buildSorted[T any](sorted []T, parent *RBNode[T], depth, redDepth int) *RBNode[T] {
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	mid := len(sorted) / 2
+	rbn := &RBNode[T]{
+		Val:     sorted[mid],
+		parent:  parent,
+		isBlack: depth == 0 || depth != redDepth,
+	}
+
+	rbn.left = buildSorted(sorted[:mid], rbn, depth+1, redDepth)
+	rbn.right = buildSorted(sorted[mid+1:], rbn, depth+1, redDepth)
+
+	return rbn
+}
+
+// RebalanceSubtree rebuilds the subtree of the node into a balanced subtree, in which the middle value is the root
+// and the halves are the subtrees recursively. The nodes are relinked rather than copied, so they keep their values.
+// The new subtree is colored to keep the black height of the rest of the tree: the black height of the sibling
+// subtree of the node is kept, or the biggest possible black height is chosen if the node is the root.
+// If a balanced subtree can't have that black height with a root color allowed by its parent, which is possible
+// only if the tree is not valid, e.g. after RotateLeft or RotateRight, the tree is left unchanged and
+// [ErrBlackHeight] is returned. RebalanceSubtree takes O(k + log n) time for the subtree of k nodes, since the sizes
+// of the subtrees are updated up to the root.
+// RebalanceSubtree returns [ErrNilNode] if the node is nil, or [ErrFrozen] if the tree is frozen.
+func (rbt *RBTree[T]) RebalanceSubtree(rbn *RBNode[T]) error {
+	if rbt.frozen {
+		return ErrFrozen
+	}
+
+	if rbn == nil {
+		return ErrNilNode
+	}
+
+	var nodes []*RBNode[T]
+
+	for node, last := rbn.leftmost(), rbn.rightmost(); ; node = node.nextNode() {
+		nodes = append(nodes, node)
+
+		if node == last {
+			break
+		}
+	}
+
+	heights := make(map[int][2]uint64)
+	black, red := balancedBlackHeightSets(len(nodes), heights)
+	parent := rbn.parent
+
+	var height int
+
+	if parent == nil {
+		height = bits.Len64(black) - 1
+	} else {
+		sibling, _ := rbn.Sibling()
+		height = sibling.blackHeight()
+	}
+
+	if black&(1<<height) == 0 && (parent == nil || !parent.isBlack || red&(1<<height) == 0) {
+		return ErrBlackHeight
+	}
+
+	isLeft := parent != nil && parent.left == rbn
+	root := rbt.linkBalanced(nodes, parent, height, heights)
+
+	switch {
+	case parent == nil:
+		rbt.root = root
+	case isLeft:
+		parent.left = root
+	default:
+		parent.right = root
+	}
+
+	rbt.updatePath(parent)
+
+	return nil
+}
+
+// linkBalanced links the sorted nodes into a balanced subtree like buildSorted and returns its root.
+// Every node is painted black if its subtree can have the black height with a black root, or red otherwise.
+// heights contains the black height sets of the subtrees by the number of their nodes (see balancedBlackHeightSets).
+func (rbt *RBTree[T]) linkBalanced(nodes []*RBNode[T], parent *RBNode[T], height int, heights map[int][2]uint64) *RBNode[T] {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	mid := len(nodes) / 2
+	rbn := nodes[mid]
+	rbn.parent = parent
+
+	black := heights[len(nodes)][0]&(1<<height) != 0
+	rbt.paint(rbn, black)
+
+	if black {
+		height--
+	}
+
+	rbn.left = rbt.linkBalanced(nodes[:mid], rbn, height, heights)
+	rbn.right = rbt.linkBalanced(nodes[mid+1:], rbn, height, heights)
+	rbt.update(rbn)
+
+	return rbn
+}
+
+// balancedBlackHeightSets returns the sets of black heights, which a subtree of count nodes built by buildSorted
+// can have in valid colorings with a black root and with a red root (see RBNode.blackHeightSets).
+// The sets are stored in heights by the number of nodes, so every distinct size is computed once.
+func balancedBlackHeightSets(count int, heights map[int][2]uint64) (uint64, uint64) {
+	if count == 0 {
+		return 1, 0
+	}
+
+	if sets, ok := heights[count]; ok {
+		return sets[0], sets[1]
+	}
+
+	leftBlack, leftRed := balancedBlackHeightSets(count/2, heights)
+	rightBlack, rightRed := balancedBlackHeightSets(count-1-count/2, heights)
+
+	black := ((leftBlack | leftRed) & (rightBlack | rightRed)) << 1
+	red := leftBlack & rightBlack
+	heights[count] = [2]uint64{black, red}
+
+	return black, red
+}
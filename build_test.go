@@ -0,0 +1,387 @@
+package rbtree
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestFromSortedSlice(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 1, 2, 3, 7, 8, 100, 1000} {
+		sorted := make([]int, size)
+		for i := range sorted {
+			sorted[i] = i
+		}
+
+		rbt := FromSortedSlice(sorted, cmp.Compare[int])
+
+		if !rbt.IsValid() || rbt.Count != size || !slices.Equal(rbt.ToSlice(), sorted) {
+			t.Fail()
+		}
+	}
+}
+
+func TestBuildFromSortedParallel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		size    int
+		workers int
+	}{
+		{"BuildFromSortedParallel: empty", 0, 4},
+		{"BuildFromSortedParallel: one value", 1, 4},
+		{"BuildFromSortedParallel: sequential", 1000, 1},
+		{"BuildFromSortedParallel: non-positive workers", 1000, -1},
+		{"BuildFromSortedParallel: perfect tree", 1<<15 - 1, 4},
+		{"BuildFromSortedParallel: odd workers", 100000, 3},
+		{"BuildFromSortedParallel: many workers", 100000, 64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			sorted := make([]int, tc.size)
+			for i := range sorted {
+				sorted[i] = i * 2
+			}
+
+			rbt := BuildFromSortedParallel(sorted, cmp.Compare[int], tc.workers)
+
+			values := make([]int, 0, rbt.Count)
+			for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+				values = append(values, rbn.Val)
+			}
+
+			if !rbt.IsValid() || rbt.Count != tc.size || !slices.Equal(values, sorted) {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func BenchmarkBuildFromSortedParallel(b *testing.B) {
+	sorted := make([]int, 10000000)
+	for i := range sorted {
+		sorted[i] = i
+	}
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run("workers-"+strconv.Itoa(workers), func(b *testing.B) {
+			for range b.N {
+				_ = BuildFromSortedParallel(sorted, cmp.Compare[int], workers)
+			}
+		})
+	}
+}
+
+func TestResetFromSorted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ResetFromSorted: replaces values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+		if err := rbt.ResetFromSorted(sorted); err != nil {
+			t.Fail()
+		}
+
+		values := make([]int, 0, rbt.Count)
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			values = append(values, rbn.Val)
+		}
+
+		if !rbt.IsValid() || rbt.Count != len(sorted) || !slices.Equal(values, sorted) || rbt.Max.Val != 10 {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Insert(0); !ok || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("ResetFromSorted: empty slice", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if err := rbt.ResetFromSorted(nil); err != nil || rbt.Count != 0 || rbt.Min != nil || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("ResetFromSorted: options", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+		_, _ = rbt.Insert(10)
+
+		if err := rbt.ResetFromSorted([]int{1, 2, 3}); err != nil || !rbt.IsValid() || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{1, 2, 3}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ResetFromSorted: errors", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if err := rbt.ResetFromSorted([]int{1, 3, 3}); !errors.Is(err, ErrNotSorted) || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+
+		rbt.Freeze()
+
+		if err := rbt.ResetFromSorted([]int{1}); !errors.Is(err, ErrFrozen) || rbt.Count != 7 {
+			t.Fail()
+		}
+	})
+}
+
+func TestRebalanceSubtree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RebalanceSubtree: root", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
+		}
+
+		vals := rbt.ToSlice()
+		height := len(rbt.DepthHistogram())
+		node, _ := rbt.Find(500)
+
+		if err := rbt.RebalanceSubtree(rbt.root); err != nil || !rbt.IsValid() {
+			t.FailNow()
+		}
+
+		if rbn, _ := rbt.Find(500); rbn != node || len(rbt.DepthHistogram()) >= height || !slices.Equal(rbt.ToSlice(), vals) {
+			t.Fail()
+		}
+	})
+
+	t.Run("RebalanceSubtree: random subtrees", func(t *testing.T) {
+		t.Parallel()
+
+		for range 100 {
+			rbt := NewOrdered(WithInsertionOrder[int]())
+
+			for range rand.IntN(300) + 1 {
+				_, _ = rbt.Insert(rand.IntN(1000))
+			}
+
+			vals := rbt.ToSlice()
+			rbn, _ := rbt.Select(rand.IntN(rbt.Count))
+
+			if err := rbt.RebalanceSubtree(rbn); err != nil || !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), vals) {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("RebalanceSubtree: errors", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 4 {
+			_, _ = rbt.Insert(i)
+		}
+
+		_ = rbt.RotateLeft(rbt.root)
+		_ = rbt.RotateLeft(rbt.root)
+		hash := rbt.StructuralHash()
+
+		if err := rbt.RebalanceSubtree(rbt.root.left); !errors.Is(err, ErrBlackHeight) || rbt.StructuralHash() != hash {
+			t.Fail()
+		}
+
+		if err := rbt.RebalanceSubtree(nil); !errors.Is(err, ErrNilNode) {
+			t.Fail()
+		}
+
+		rbt.Freeze()
+
+		if err := rbt.RebalanceSubtree(rbt.root); !errors.Is(err, ErrFrozen) {
+			t.Fail()
+		}
+	})
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Split: existing value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		smaller, rest := rbt.Split(70)
+
+		if !smaller.IsValid() || !rest.IsValid() || !slices.Equal(smaller.ToSlice(), []int{20, 50, 60}) || !slices.Equal(rest.ToSlice(), []int{70, 75, 80, 100}) {
+			t.Fail()
+		}
+
+		if !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Split: bounds", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if smaller, rest := rbt.Split(0); smaller.Count != 0 || rest.Count != 7 || !smaller.IsValid() || !rest.IsValid() {
+			t.Fail()
+		}
+
+		if smaller, rest := rbt.Split(1000); smaller.Count != 7 || rest.Count != 0 || !smaller.IsValid() || !rest.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Split: random trees", func(t *testing.T) {
+		t.Parallel()
+
+		for range 100 {
+			rbt := NewOrdered(WithInsertionOrder[int]())
+
+			for range rand.IntN(300) {
+				_, _ = rbt.Insert(rand.IntN(1000))
+			}
+
+			pivot := rand.IntN(1000)
+			smaller, rest := rbt.Split(pivot)
+
+			if !smaller.IsValid() || !rest.IsValid() || !slices.Equal(append(smaller.ToSlice(), rest.ToSlice()...), rbt.ToSlice()) {
+				t.FailNow()
+			}
+
+			if smaller.Count > 0 && smaller.Max.Val >= pivot || rest.Count > 0 && rest.Min.Val < pivot {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("Split: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 2, 3, 3, 3)
+		smaller, rest := rbt.Split(3)
+
+		if !smaller.IsValid() || !rest.IsValid() || !slices.Equal(smaller.ToSlice(), []int{1, 1, 2}) || rest.Count != 3 {
+			t.Fail()
+		}
+	})
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Join: random trees", func(t *testing.T) {
+		t.Parallel()
+
+		for range 300 {
+			rbt, other := NewOrdered[int](), NewOrdered[int]()
+
+			for range rand.IntN(500) {
+				_, _ = rbt.Insert(rand.IntN(1000))
+			}
+
+			for range rand.IntN(500) {
+				_, _ = other.Insert(1000 + rand.IntN(1000))
+			}
+
+			expected := append(rbt.ToSlice(), other.ToSlice()...)
+
+			if err := rbt.Join(other); err != nil || !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), expected) {
+				t.FailNow()
+			}
+
+			if other.Count != 0 || other.root != nil || !other.IsValid() {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("Join: different heights", func(t *testing.T) {
+		t.Parallel()
+
+		for _, sizes := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, 1000}, {1000, 1}, {2, 100000}, {100000, 2}} {
+			rbt, other := NewOrdered[int](), NewOrdered[int]()
+
+			for i := range sizes[0] {
+				_, _ = rbt.Insert(i)
+			}
+
+			for i := range sizes[1] {
+				_, _ = other.Insert(sizes[0] + i)
+			}
+
+			if err := rbt.Join(other); err != nil || !rbt.IsValid() || rbt.Count != sizes[0]+sizes[1] {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("Join: insertion order", func(t *testing.T) {
+		t.Parallel()
+
+		rbt, other := NewOrdered(WithInsertionOrder[int]()), NewOrdered(WithInsertionOrder[int]())
+		rbt.InsertAll(3, 1, 2)
+		other.InsertAll(6, 4, 5)
+
+		if err := rbt.Join(other); err != nil || !rbt.IsValid() || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{3, 1, 2, 6, 4, 5}) {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Delete(4); !ok || !rbt.IsValid() || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{3, 1, 2, 6, 5}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Join: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt, other := NewMultiset(cmp.Compare[int]), NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 2)
+		other.InsertAll(3, 3, 3, 4)
+
+		if err := rbt.Join(other); err != nil || !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), []int{1, 1, 2, 3, 3, 3, 4}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Join: errors", func(t *testing.T) {
+		t.Parallel()
+
+		rbt, other := initRBTBefore(), NewOrdered[int]()
+		other.InsertAll(100, 200)
+
+		if err := rbt.Join(other); !errors.Is(err, ErrNotSorted) || !rbt.EqualTo(initRBTBefore()) || other.Count != 2 {
+			t.Fail()
+		}
+
+		if err := rbt.Join(nil); err != nil || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+
+		rbt.Freeze()
+
+		if err := rbt.Join(NewOrdered[int]()); !errors.Is(err, ErrFrozen) {
+			t.Fail()
+		}
+	})
+}
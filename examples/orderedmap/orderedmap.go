@@ -49,7 +49,7 @@ func main() {
 
 	fmt.Println("Traversing map from Max to Min:")
 
-	for rbNode, ok := rbTree.Max, rbTree.Max != nil; ok; rbNode, ok = rbNode.Prev() {
+	for rbNode := range rbTree.NodesBackward() {
 		fmt.Printf("%v ", rbNode.Val)
 	}
 
@@ -0,0 +1,62 @@
+package rbtree
+
+import "sync/atomic"
+
+// GroupBy walks the tree in order and groups its values by the keys derived with key.
+// The returned map is unordered, but the values in every group are sorted in ascending order.
+// In a multiset (see NewMultiset), every occurrence of a value is put in its group.
+func GroupBy[T any, K comparable](rbt *RBTree[T], key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		k := key(rbn.Val)
+
+		for range rbn.Multiplicity() {
+			groups[k] = append(groups[k], rbn.Val)
+		}
+	}
+
+	return groups
+}
+
+// SumRange folds the values of the tree in the range [lo, hi] into an accumulator in ascending order,
+// starting with init and combining the accumulator with every value using add.
+// SumRange seeks to lo, so it takes O(log n + k) time for k values in the range.
+// If lo > hi, init is returned. In a multiset (see NewMultiset), every occurrence of a value is added.
+func SumRange[T, A any](rbt *RBTree[T], lo, hi T, init A, add func(A, T) A) A {
+	acc := init
+
+	for rbn := rbt.ceilingNode(lo); rbn != nil && rbt.cmp(rbn.Val, hi) <= 0; rbn = rbn.nextNode() {
+		for range rbn.Multiplicity() {
+			acc = add(acc, rbn.Val)
+		}
+	}
+
+	return acc
+}
+
+// Map applies f to every value of the tree in ascending order and returns a new tree of the results ordered by cmp.
+// Since f does not have to preserve the order, the results are inserted one by one, which takes O(n log n) time.
+// Results comparing equal to an earlier result are skipped like in Insert. The options of the tree are not copied.
+func Map[T, U any](rbt *RBTree[T], f func(T) U, cmp func(U, U) int) *RBTree[U] {
+	mapped := New(cmp)
+
+	for val := range rbt.All() {
+		_, _ = mapped.Insert(f(val))
+	}
+
+	return mapped
+}
+
+// CountingCmp wraps the comparison function, so that every call increments the returned counter atomically.
+// Passing the wrapped function to New measures the amount of comparisons performed by all operations on the tree,
+// e.g. in benchmarks. The counter can be read with [atomic.LoadInt64] and reset with [atomic.StoreInt64].
+func CountingCmp[T any](cmp func(T, T) int) (func(T, T) int, *int64) {
+	var count int64
+
+	return func(first, second T) int {
+		atomic.AddInt64(&count, 1)
+
+		return cmp(first, second)
+	}, &count
+}
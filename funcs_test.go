@@ -0,0 +1,158 @@
+package rbtree
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GroupBy: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		groups := GroupBy(NewOrdered[int](), func(val int) int { return val % 2 })
+		if groups == nil || len(groups) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("GroupBy: sorted groups", func(t *testing.T) {
+		t.Parallel()
+
+		groups := GroupBy(initRBTBefore(), func(val int) bool { return val%20 == 0 })
+
+		if len(groups) != 2 || !slices.Equal(groups[true], []int{20, 60, 80, 100}) || !slices.Equal(groups[false], []int{50, 70, 75}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("GroupBy: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 2, 3, 3)
+
+		groups := GroupBy(rbt, func(val int) int { return val % 2 })
+
+		if len(groups) != 2 || !slices.Equal(groups[1], []int{1, 1, 3, 3}) || !slices.Equal(groups[0], []int{2}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestSumRange(t *testing.T) {
+	t.Parallel()
+
+	sum := func(acc int, val int) int {
+		return acc + val
+	}
+
+	testCases := []struct {
+		name     string
+		lo, hi   int
+		expected int
+	}{
+		{"SumRange: inner range", 55, 78, 1 + 60 + 70 + 75},
+		{"SumRange: exact bounds", 20, 50, 1 + 20 + 50},
+		{"SumRange: empty range", 61, 69, 1},
+		{"SumRange: reversed bounds", 80, 50, 1},
+		{"SumRange: whole tree", 0, 1000, 1 + 20 + 50 + 60 + 70 + 75 + 80 + 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if SumRange(initRBTBefore(), tc.lo, tc.hi, 1, sum) != tc.expected {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("SumRange: different accumulator type", func(t *testing.T) {
+		t.Parallel()
+
+		count := SumRange(initRBTBefore(), 50, 80, "", func(acc string, _ int) string { return acc + "." })
+		if count != "....." {
+			t.Fail()
+		}
+	})
+
+	t.Run("SumRange: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 2, 3, 3, 4)
+
+		if SumRange(rbt, 1, 3, 0, sum) != 1+1+2+3+3 {
+			t.Fail()
+		}
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	type key struct {
+		group int
+		id    int
+	}
+
+	compareKeys := func(a, b key) int {
+		return cmp.Or(cmp.Compare(a.group, b.group), cmp.Compare(a.id, b.id))
+	}
+
+	t.Run("Map: struct keys", func(t *testing.T) {
+		t.Parallel()
+
+		mapped := Map(initRBTBefore(), func(val int) key { return key{group: val % 3, id: val} }, compareKeys)
+		expected := []key{{0, 60}, {0, 75}, {1, 70}, {1, 100}, {2, 20}, {2, 50}, {2, 80}}
+
+		if !mapped.IsValid() || !slices.Equal(mapped.ToSlice(), expected) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Map: equal results", func(t *testing.T) {
+		t.Parallel()
+
+		mapped := Map(initRBTBefore(), func(val int) int { return val / 50 }, cmp.Compare[int])
+
+		if !mapped.IsValid() || !slices.Equal(mapped.ToSlice(), []int{0, 1, 2}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Map: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if mapped := Map(NewOrdered[int](), strconv.Itoa, strings.Compare); mapped.Count != 0 || !mapped.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestCountingCmp(t *testing.T) {
+	t.Parallel()
+
+	compare, count := CountingCmp(cmp.Compare[int])
+	rbt := New(compare)
+
+	if _, ok := rbt.Insert(1); !ok || atomic.LoadInt64(count) != 0 {
+		t.Fail()
+	}
+
+	_, _ = rbt.Insert(2)
+	atomic.StoreInt64(count, 0)
+
+	_, _ = rbt.Find(2)
+
+	if atomic.LoadInt64(count) != 2 || compare(2, 1) != 1 || atomic.LoadInt64(count) != 3 {
+		t.Fail()
+	}
+}
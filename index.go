@@ -0,0 +1,97 @@
+package rbtree
+
+import (
+	"iter"
+)
+
+// SecondaryIndex is a view of an ordered map, which orders its key-value pairs by values.
+// The index is a separate red-black tree, which is kept in sync with the map: Insert, Upsert and Delete
+// of the map update all its indexes, so every mutation of the map takes O(log n) time per index.
+// Pairs with equal values are ordered by their keys.
+type SecondaryIndex[K, V any] struct {
+	tree   *RBTree[Pair[K, V]]
+	cmpVal func(V, V) int
+}
+
+// AddIndex registers a secondary index of the map ordered by values with cmp, which compares values
+// the same way as the comparison function of New. The pairs stored in the map are added to the index in O(n log n) time.
+func (kv *KV[K, V]) AddIndex(cmp func(V, V) int) *SecondaryIndex[K, V] {
+	index := &SecondaryIndex[K, V]{
+		tree: New(func(first, second Pair[K, V]) int {
+			if result := cmp(first.Val, second.Val); result != 0 {
+				return result
+			}
+
+			return kv.cmpKey(first.Key, second.Key)
+		}),
+		cmpVal: cmp,
+	}
+
+	for rbn := kv.tree.Min; rbn != nil; rbn = rbn.nextNode() {
+		_, _ = index.tree.Insert(rbn.Val)
+	}
+
+	kv.indexes = append(kv.indexes, index)
+
+	return index
+}
+
+// indexInsert adds the key-value pair to every secondary index of the map.
+func (kv *KV[K, V]) indexInsert(key K, val V) {
+	for _, index := range kv.indexes {
+		_, _ = index.tree.Insert(Pair[K, V]{Key: key, Val: val})
+	}
+}
+
+// indexDelete deletes the key-value pair from every secondary index of the map.
+func (kv *KV[K, V]) indexDelete(key K, val V) {
+	for _, index := range kv.indexes {
+		_, _ = index.tree.Delete(Pair[K, V]{Key: key, Val: val})
+	}
+}
+
+// Len returns the number of key-value pairs in the index, which is equal to the number of pairs in the map.
+func (index *SecondaryIndex[K, V]) Len() int {
+	return index.tree.Count
+}
+
+// Find returns the smallest key associated with the value and true if the value exists in the map.
+func (index *SecondaryIndex[K, V]) Find(val V) (K, bool) {
+	rbn := index.ceilingNode(val)
+	if rbn == nil || index.cmpVal(rbn.Val.Val, val) != 0 {
+		var key K
+
+		return key, false
+	}
+
+	return rbn.Val.Key, true
+}
+
+// Range returns an iterator over the key-value pairs with values in the range [lo, hi] in ascending order of values.
+// Range seeks to lo, so it takes O(log n + k) time to yield k pairs. If lo > hi, nothing is yielded.
+func (index *SecondaryIndex[K, V]) Range(lo, hi V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for rbn := index.ceilingNode(lo); rbn != nil && index.cmpVal(rbn.Val.Val, hi) <= 0; rbn = rbn.nextNode() {
+			if !yield(rbn.Val.Key, rbn.Val.Val) {
+				return
+			}
+		}
+	}
+}
+
+// ceilingNode returns the first node with a value bigger than or equal to val or nil if there is no such node.
+// Only values are compared, so the node with the smallest key is returned among the nodes with equal values.
+func (index *SecondaryIndex[K, V]) ceilingNode(val V) *RBNode[Pair[K, V]] {
+	var ceiling *RBNode[Pair[K, V]]
+
+	for rbn := index.tree.root; rbn != nil; {
+		if index.cmpVal(rbn.Val.Val, val) >= 0 {
+			ceiling = rbn
+			rbn = rbn.left
+		} else {
+			rbn = rbn.right
+		}
+	}
+
+	return ceiling
+}
@@ -0,0 +1,96 @@
+package rbtree
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestSecondaryIndex(t *testing.T) {
+	t.Parallel()
+
+	collect := func(index *SecondaryIndex[string, int], lo, hi int) []Pair[string, int] {
+		pairs := []Pair[string, int]{}
+
+		for key, val := range index.Range(lo, hi) {
+			pairs = append(pairs, Pair[string, int]{key, val})
+		}
+
+		return pairs
+	}
+
+	t.Run("SecondaryIndex: existing pairs", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[string, int]()
+		_ = kv.Insert("c", 1)
+		_ = kv.Insert("a", 3)
+		_ = kv.Insert("b", 1)
+
+		index := kv.AddIndex(cmp.Compare[int])
+
+		if key, ok := index.Find(1); !ok || key != "b" || index.Len() != 3 || !index.tree.IsValid() {
+			t.Fail()
+		}
+
+		if _, ok := index.Find(2); ok {
+			t.Fail()
+		}
+
+		if !slices.Equal(collect(index, 0, 5), []Pair[string, int]{{"b", 1}, {"c", 1}, {"a", 3}}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("SecondaryIndex: synchronized mutations", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[string, int]()
+		index := kv.AddIndex(cmp.Compare[int])
+		sum := func(existent, val int) int {
+			return existent + val
+		}
+
+		_ = kv.Insert("a", 10)
+		_ = kv.Insert("b", 20)
+		_ = kv.Insert("a", 30)
+		_, _ = kv.Upsert("c", 5, sum)
+		_, _ = kv.Upsert("b", 5, sum)
+		_, _ = kv.Delete("a")
+		_, _ = kv.Delete("z")
+
+		if index.Len() != kv.Len() || !index.tree.IsValid() {
+			t.Fail()
+		}
+
+		if !slices.Equal(collect(index, 0, 100), []Pair[string, int]{{"c", 5}, {"b", 25}}) {
+			t.Fail()
+		}
+
+		if !slices.Equal(collect(index, 6, 25), []Pair[string, int]{{"b", 25}}) || len(collect(index, 25, 6)) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("SecondaryIndex: multiple indexes", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[string, int]()
+		ascending := kv.AddIndex(cmp.Compare[int])
+		descending := kv.AddIndex(func(first, second int) int {
+			return cmp.Compare(second, first)
+		})
+
+		for i, key := range []string{"a", "b", "c"} {
+			_ = kv.Insert(key, i)
+		}
+
+		if key, ok := ascending.Find(0); !ok || key != "a" {
+			t.Fail()
+		}
+
+		if !slices.Equal(collect(descending, 2, 0), []Pair[string, int]{{"c", 2}, {"b", 1}, {"a", 0}}) {
+			t.Fail()
+		}
+	})
+}
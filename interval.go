@@ -0,0 +1,121 @@
+package rbtree
+
+import (
+	"iter"
+)
+
+// IntervalTree is a red-black tree of closed intervals. Every node is augmented with the node of its subtree
+// with the biggest high endpoint, which allows to skip subtrees that cannot contain matching intervals.
+// The intervals are ordered by their low endpoints and then by their high endpoints.
+// All methods of RBTree are available and maintain the augmentation.
+type IntervalTree[T, P any] struct {
+	*RBTree[T]
+	bounds   func(T) (P, P)
+	cmpPoint func(P, P) int
+}
+
+// NewIntervalTree returns an empty interval tree.
+// bounds returns the low and the high endpoints of an interval.
+// cmp compares endpoints the same way as the comparison function of New.
+// Intervals with equal endpoints are considered equal, so only one of them can be stored in the tree.
+func NewIntervalTree[T, P any](bounds func(T) (P, P), cmp func(P, P) int, opts ...Option[T]) *IntervalTree[T, P] {
+	compareIntervals := func(first, second T) int {
+		firstLow, firstHigh := bounds(first)
+		secondLow, secondHigh := bounds(second)
+
+		if result := cmp(firstLow, secondLow); result != 0 {
+			return result
+		}
+
+		return cmp(firstHigh, secondHigh)
+	}
+
+	rbt := New(compareIntervals, opts...)
+	rbt.cmpEnd = func(first, second T) int {
+		_, firstHigh := bounds(first)
+		_, secondHigh := bounds(second)
+
+		return cmp(firstHigh, secondHigh)
+	}
+
+	return &IntervalTree[T, P]{
+		RBTree:   rbt,
+		bounds:   bounds,
+		cmpPoint: cmp,
+	}
+}
+
+// Stab returns an iterator over the intervals containing the point in ascending order.
+// The subtrees, whose intervals all end before the point, are skipped, so Stab takes O(min(n, k log n)) time
+// for k yielded intervals.
+func (it *IntervalTree[T, P]) Stab(point P) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		it.stab(it.root, point, yield)
+	}
+}
+
+// stab yields the intervals of the subtree containing the point. It returns false if the iteration was stopped.
+func (it *IntervalTree[T, P]) stab(rbn *RBNode[T], point P, yield func(T) bool) bool {
+	if rbn == nil {
+		return true
+	}
+
+	if _, maxHigh := it.bounds(rbn.maxEnd.Val); it.cmpPoint(maxHigh, point) < 0 { // all intervals end before the point
+		return true
+	}
+
+	if !it.stab(rbn.left, point, yield) {
+		return false
+	}
+
+	low, high := it.bounds(rbn.Val)
+	if it.cmpPoint(low, point) > 0 { // this interval and all intervals to the right start after the point
+		return true
+	}
+
+	if it.cmpPoint(high, point) >= 0 && !yield(rbn.Val) {
+		return false
+	}
+
+	return it.stab(rbn.right, point, yield)
+}
+
+// Overlapping returns an iterator over the intervals overlapping the range [lo, hi] in ascending order.
+// Like in Stab, the subtrees are pruned by their biggest high endpoint, so Overlapping takes O(min(n, k log n)) time
+// for k yielded intervals. If lo > hi, the iterator yields nothing.
+func (it *IntervalTree[T, P]) Overlapping(lo, hi P) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if it.cmpPoint(lo, hi) > 0 {
+			return
+		}
+
+		it.overlapping(it.root, lo, hi, yield)
+	}
+}
+
+// overlapping yields the intervals of the subtree overlapping the range [lo, hi].
+// It returns false if the iteration was stopped.
+func (it *IntervalTree[T, P]) overlapping(rbn *RBNode[T], lo, hi P, yield func(T) bool) bool {
+	if rbn == nil {
+		return true
+	}
+
+	if _, maxHigh := it.bounds(rbn.maxEnd.Val); it.cmpPoint(maxHigh, lo) < 0 { // all intervals end before the range
+		return true
+	}
+
+	if !it.overlapping(rbn.left, lo, hi, yield) {
+		return false
+	}
+
+	low, high := it.bounds(rbn.Val)
+	if it.cmpPoint(low, hi) > 0 { // this interval and all intervals to the right start after the range
+		return true
+	}
+
+	if it.cmpPoint(high, lo) >= 0 && !yield(rbn.Val) {
+		return false
+	}
+
+	return it.overlapping(rbn.right, lo, hi, yield)
+}
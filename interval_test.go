@@ -0,0 +1,175 @@
+package rbtree
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+type interval struct {
+	low, high int
+}
+
+func intervalBounds(i interval) (int, int) {
+	return i.low, i.high
+}
+
+func newRandomIntervalTree(size int) (*IntervalTree[interval, int], []interval) {
+	it := NewIntervalTree(intervalBounds, cmp.Compare[int])
+
+	var intervals []interval
+
+	for range size {
+		low := rand.IntN(1000)
+		i := interval{low: low, high: low + rand.IntN(100)}
+
+		if _, ok := it.Insert(i); ok {
+			intervals = append(intervals, i)
+		}
+	}
+
+	slices.SortFunc(intervals, it.cmp)
+
+	return it, intervals
+}
+
+func TestIntervalTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IntervalTree: random insert and delete", func(t *testing.T) {
+		t.Parallel()
+
+		it, intervals := newRandomIntervalTree(1000)
+
+		if !it.IsValid() {
+			t.FailNow()
+		}
+
+		for _, i := range intervals[:500] {
+			_, _ = it.Delete(i)
+
+			if !it.IsValid() {
+				t.FailNow()
+			}
+		}
+
+		if !it.Clone().IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("IntervalTree: stale augmentation", func(t *testing.T) {
+		t.Parallel()
+
+		it, _ := newRandomIntervalTree(100)
+
+		it.root.maxEnd = it.root
+
+		if it.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestStab(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Stab: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		it := NewIntervalTree(intervalBounds, cmp.Compare[int])
+
+		if len(slices.Collect(it.Stab(10))) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Stab: endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		it := NewIntervalTree(intervalBounds, cmp.Compare[int])
+
+		for _, i := range []interval{{1, 5}, {5, 10}, {6, 8}, {0, 20}, {11, 12}} {
+			_, _ = it.Insert(i)
+		}
+
+		if !slices.Equal(slices.Collect(it.Stab(5)), []interval{{0, 20}, {1, 5}, {5, 10}}) {
+			t.Fail()
+		}
+
+		if len(slices.Collect(it.Stab(21))) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Stab: random", func(t *testing.T) {
+		t.Parallel()
+
+		it, intervals := newRandomIntervalTree(1000)
+
+		for range 100 {
+			point := rand.IntN(1200)
+
+			expected := slices.DeleteFunc(slices.Clone(intervals), func(i interval) bool {
+				return i.low > point || i.high < point
+			})
+
+			if !slices.Equal(slices.Collect(it.Stab(point)), expected) {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestOverlapping(t *testing.T) {
+	t.Parallel()
+
+	it := NewIntervalTree(intervalBounds, cmp.Compare[int])
+
+	for _, i := range []interval{{0, 100}, {10, 20}, {12, 15}, {20, 30}, {31, 40}, {50, 60}} {
+		_, _ = it.Insert(i)
+	}
+
+	testCases := []struct {
+		name     string
+		lo, hi   int
+		expected []interval
+	}{
+		{"Overlapping: nested", 13, 14, []interval{{0, 100}, {10, 20}, {12, 15}}},
+		{"Overlapping: adjacent", 20, 20, []interval{{0, 100}, {10, 20}, {20, 30}}},
+		{"Overlapping: disjoint", 41, 49, []interval{{0, 100}}},
+		{"Overlapping: enclosing", 25, 55, []interval{{0, 100}, {20, 30}, {31, 40}, {50, 60}}},
+		{"Overlapping: beyond all intervals", 101, 200, nil},
+		{"Overlapping: reversed bounds", 20, 10, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if !slices.Equal(slices.Collect(it.Overlapping(tc.lo, tc.hi)), tc.expected) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Overlapping: random", func(t *testing.T) {
+		t.Parallel()
+
+		it, intervals := newRandomIntervalTree(1000)
+
+		for range 100 {
+			lo := rand.IntN(1200)
+			hi := lo + rand.IntN(50)
+
+			expected := slices.DeleteFunc(slices.Clone(intervals), func(i interval) bool {
+				return i.low > hi || i.high < lo
+			})
+
+			if !slices.Equal(slices.Collect(it.Overlapping(lo, hi)), expected) {
+				t.FailNow()
+			}
+		}
+	})
+}
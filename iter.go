@@ -0,0 +1,186 @@
+package rbtree
+
+import (
+	"iter"
+	"strings"
+)
+
+// WithPrefix returns an iterator over the values of the tree starting with prefix in ascending order.
+// The tree must be ordered lexicographically (e.g. created with NewOrdered).
+// WithPrefix seeks to the first value not smaller than prefix, so it takes O(log n + k) time to yield k values.
+// An empty prefix yields all values of the tree.
+func WithPrefix[T ~string](rbt *RBTree[T], prefix T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for rbn := rbt.ceilingNode(prefix); rbn != nil && strings.HasPrefix(string(rbn.Val), string(prefix)); rbn, _ = rbn.Next() {
+			if !yield(rbn.Val) {
+				return
+			}
+		}
+	}
+}
+
+// Number is a constraint for numeric types supporting addition and subtraction.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// WithinDistance returns an iterator over the values v of the tree with |v - center| <= radius in ascending order.
+// The tree must be ordered ascending (e.g. created with NewOrdered). WithinDistance seeks to center - radius,
+// so it takes O(log n + k) time to yield k values. The bounds are clamped if they overflow.
+// A zero radius yields the center only if it is stored, a negative radius yields nothing.
+func WithinDistance[T Number](rbt *RBTree[T], center, radius T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if radius < 0 {
+			return
+		}
+
+		lo, hi := center-radius, center+radius
+
+		rbn := rbt.ceilingNode(lo)
+		if lo > center { // underflow
+			rbn = rbt.Min
+		}
+
+		for ; rbn != nil && (rbn.Val <= hi || hi < center); rbn = rbn.nextNode() {
+			if !yield(rbn.Val) {
+				return
+			}
+		}
+	}
+}
+
+// InsertionOrder returns an iterator over the values of the tree from the oldest to the newest one.
+// It yields nothing if the tree does not track the insertion order (see WithInsertionOrder).
+func (rbt *RBTree[T]) InsertionOrder() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for rbn := rbt.oldest; rbn != nil; rbn = rbn.newer {
+			if !yield(rbn.Val) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the values of the tree in the range [lo, hi] in ascending order.
+// Range seeks to lo, so it takes O(log n + k) time to yield k values. If lo > hi, nothing is yielded.
+func (rbt *RBTree[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for rbn := rbt.ceilingNode(lo); rbn != nil && rbt.cmp(rbn.Val, hi) <= 0; rbn = rbn.nextNode() {
+			if !rbn.yieldCopies(yield) {
+				return
+			}
+		}
+	}
+}
+
+// RangeFunc returns an iterator over the values of the tree in the range [lo, hi], which satisfy pred, in ascending order.
+// RangeFunc seeks to lo like Range and calls pred for every value in the range. If lo > hi, nothing is yielded.
+func (rbt *RBTree[T]) RangeFunc(lo, hi T, pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for rbn := rbt.ceilingNode(lo); rbn != nil && rbt.cmp(rbn.Val, hi) <= 0; rbn = rbn.nextNode() {
+			if pred(rbn.Val) && !rbn.yieldCopies(yield) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the values of the tree in ascending order.
+func (rbt *RBTree[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			if !rbn.yieldCopies(yield) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the values of the tree in descending order.
+func (rbt *RBTree[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for rbn := rbt.Max; rbn != nil; rbn, _ = rbn.Prev() {
+			if !rbn.yieldCopies(yield) {
+				return
+			}
+		}
+	}
+}
+
+// Nodes returns an iterator over the nodes of the tree in ascending order.
+// Modifying the structure of the tree (e.g. inserting or deleting values) during the iteration is undefined,
+// but modifying the fields of a yielded value, which do not affect its ordering, is allowed.
+func (rbt *RBTree[T]) Nodes() iter.Seq[*RBNode[T]] {
+	return func(yield func(*RBNode[T]) bool) {
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			if !yield(rbn) {
+				return
+			}
+		}
+	}
+}
+
+// NodesBackward returns an iterator over the nodes of the tree in descending order.
+// The same restrictions as for Nodes apply.
+func (rbt *RBTree[T]) NodesBackward() iter.Seq[*RBNode[T]] {
+	return func(yield func(*RBNode[T]) bool) {
+		for rbn := rbt.Max; rbn != nil; rbn, _ = rbn.Prev() {
+			if !yield(rbn) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls fn for every node of the tree in ascending order until fn returns false.
+// It is the callback equivalent of Nodes, and the same restrictions apply.
+func (rbt *RBTree[T]) ForEach(fn func(*RBNode[T]) bool) {
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		if !fn(rbn) {
+			return
+		}
+	}
+}
+
+// ForEachRange calls fn for every node of the tree with the value in the range [lo, hi] in ascending order
+// until fn returns false. ForEachRange seeks to lo like Range. If lo > hi, fn is not called.
+func (rbt *RBTree[T]) ForEachRange(lo, hi T, fn func(*RBNode[T]) bool) {
+	for rbn := rbt.ceilingNode(lo); rbn != nil && rbt.cmp(rbn.Val, hi) <= 0; rbn = rbn.nextNode() {
+		if !fn(rbn) {
+			return
+		}
+	}
+}
+
+// ForEachBatch walks the tree in ascending order and calls fn for every batch of up to size consecutive values:
+// for every full batch and once for the final partial one. The walk stops at the first error returned by fn,
+// which is returned by ForEachBatch. The batch slice is reused between the calls, so fn must not retain it.
+// size is clamped to at least 1. fn is not called for an empty tree.
+// In a multiset (see NewMultiset), every occurrence of a value is put in the batches.
+func (rbt *RBTree[T]) ForEachBatch(size int, fn func(batch []T) error) error {
+	batch := make([]T, 0, min(max(size, 1), rbt.Count))
+
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		for range rbn.Multiplicity() {
+			batch = append(batch, rbn.Val)
+
+			if len(batch) < max(size, 1) {
+				continue
+			}
+
+			if err := fn(batch); err != nil {
+				return err
+			}
+
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+
+	return nil
+}
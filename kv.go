@@ -0,0 +1,101 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Pair is a key-value pair stored in an ordered map.
+type Pair[K, V any] struct {
+	Key K
+	Val V
+}
+
+// KV is an ordered map: a red-black tree of key-value pairs ordered by their keys.
+type KV[K, V any] struct {
+	tree   *RBTree[Pair[K, V]]
+	cmpKey func(K, K) int
+	// indexes are the secondary indexes of the map, which are updated on every mutation.
+	indexes []*SecondaryIndex[K, V]
+}
+
+// NewKV returns an empty ordered map. cmp compares keys the same way as the comparison function of New.
+func NewKV[K, V any](cmp func(K, K) int) *KV[K, V] {
+	return &KV[K, V]{
+		tree: New(func(first, second Pair[K, V]) int {
+			return cmp(first.Key, second.Key)
+		}),
+		cmpKey: cmp,
+	}
+}
+
+// NewOrderedKV returns an empty ordered map for primitive key types ([cmp.Ordered]).
+func NewOrderedKV[K cmp.Ordered, V any]() *KV[K, V] {
+	return NewKV[K, V](cmp.Compare[K])
+}
+
+// Len returns the number of key-value pairs in the map.
+func (kv *KV[K, V]) Len() int {
+	return kv.tree.Count
+}
+
+// Get returns the value associated with the key and true if the key exists in the map.
+func (kv *KV[K, V]) Get(key K) (V, bool) {
+	rbn, ok := kv.tree.Find(Pair[K, V]{Key: key})
+	if !ok {
+		var val V
+
+		return val, false
+	}
+
+	return rbn.Val.Val, true
+}
+
+// Insert adds the key-value pair to the map and returns true if the key did not exist.
+// Otherwise the map is left unchanged and false is returned.
+func (kv *KV[K, V]) Insert(key K, val V) bool {
+	_, ok := kv.tree.Insert(Pair[K, V]{Key: key, Val: val})
+	if ok {
+		kv.indexInsert(key, val)
+	}
+
+	return ok
+}
+
+// Upsert adds the key-value pair to the map if the key does not exist.
+// Otherwise the value stored for the key is replaced with the result of merge(existent, val) in place.
+// Upsert returns the value stored for the key and true if the key was newly inserted.
+func (kv *KV[K, V]) Upsert(key K, val V, merge func(existent, val V) V) (V, bool) {
+	rbn, ok := kv.tree.Insert(Pair[K, V]{Key: key, Val: val})
+	if !ok {
+		kv.indexDelete(key, rbn.Val.Val)
+		rbn.Val.Val = merge(rbn.Val.Val, val)
+	}
+
+	kv.indexInsert(key, rbn.Val.Val)
+
+	return rbn.Val.Val, ok
+}
+
+// Delete deletes the key from the map.
+// Delete returns the deleted value and true if the key existed. It returns an empty value and false otherwise.
+func (kv *KV[K, V]) Delete(key K) (V, bool) {
+	pair, ok := kv.tree.Delete(Pair[K, V]{Key: key})
+	if ok {
+		kv.indexDelete(key, pair.Val)
+	}
+
+	return pair.Val, ok
+}
+
+// Keys returns an iterator over the keys of the map in ascending order.
+// Only the keys are yielded, the values are neither accessed nor copied.
+func (kv *KV[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for rbn := kv.tree.Min; rbn != nil; rbn = rbn.nextNode() {
+			if !yield(rbn.Val.Key) {
+				return
+			}
+		}
+	}
+}
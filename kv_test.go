@@ -0,0 +1,109 @@
+package rbtree
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestKV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("KV: insert, get and delete", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[string, int]()
+
+		if !kv.Insert("a", 1) || !kv.Insert("b", 2) || kv.Insert("a", 3) || kv.Len() != 2 {
+			t.Fail()
+		}
+
+		if val, ok := kv.Get("a"); !ok || val != 1 {
+			t.Fail()
+		}
+
+		if val, ok := kv.Delete("a"); !ok || val != 1 || kv.Len() != 1 {
+			t.Fail()
+		}
+
+		if _, ok := kv.Get("a"); ok {
+			t.Fail()
+		}
+
+		if _, ok := kv.Delete("a"); ok || !kv.tree.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestUpsert(t *testing.T) {
+	t.Parallel()
+
+	sum := func(existent, val int) int {
+		return existent + val
+	}
+
+	t.Run("Upsert: new key", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[string, int]()
+
+		if val, inserted := kv.Upsert("a", 5, sum); !inserted || val != 5 || kv.Len() != 1 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Upsert: counters", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[int, int]()
+
+		for i := range 1000 {
+			_, _ = kv.Upsert(i%10, i, sum)
+		}
+
+		if kv.Len() != 10 || !kv.tree.IsValid() {
+			t.Fail()
+		}
+
+		for key := range 10 {
+			if val, ok := kv.Get(key); !ok || val != 100*key+49500 {
+				t.Fail()
+			}
+		}
+	})
+}
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Keys: empty map", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(NewOrderedKV[int, string]().Keys())) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Keys: sorted keys", func(t *testing.T) {
+		t.Parallel()
+
+		kv := NewOrderedKV[int, string]()
+
+		for _, key := range []int{5, 3, 9, 1} {
+			_ = kv.Insert(key, strconv.Itoa(key))
+		}
+
+		if !slices.Equal(slices.Collect(kv.Keys()), []int{1, 3, 5, 9}) {
+			t.Fail()
+		}
+
+		for key := range kv.Keys() {
+			if key != 1 {
+				t.Fail()
+			}
+
+			break
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package rbtree
+
+// EvictPolicy selects the value evicted from a tree, which is limited with WithMaxCount.
+type EvictPolicy int
+
+const (
+	// EvictMin evicts the smallest value, so the tree keeps the biggest values.
+	EvictMin EvictPolicy = iota
+	// EvictMax evicts the biggest value, so the tree keeps the smallest values.
+	EvictMax
+	// EvictOldest evicts the value inserted first, so the tree keeps the latest values.
+	EvictOldest
+)
+
+// Option configures a red-black tree created with New or NewOrdered.
+type Option[T any] func(*RBTree[T])
+
+// WithInsertionOrder makes the tree track the insertion order of its values alongside the sorted order.
+// The nodes are additionally linked into a list in the order their values were inserted,
+// which costs two pointers per node and O(1) time per insertion or deletion.
+// The insertion order can be traversed with InsertionOrder, and its ends are returned by Oldest and Newest.
+func WithInsertionOrder[T any]() Option[T] {
+	return func(rbt *RBTree[T]) {
+		rbt.insertionOrder = true
+	}
+}
+
+// WithTieBreaker makes the tree order values, which are equal according to the comparison function,
+// with tieBreaker. It allows to store distinct values compared by a subset of their fields
+// (otherwise only the first inserted one of them is stored).
+// Since tieBreaker is a part of the ordering, Find, Delete and other searches match a value
+// only if both the comparison function and tieBreaker return 0.
+func WithTieBreaker[T any](tieBreaker func(T, T) int) Option[T] {
+	return func(rbt *RBTree[T]) {
+		cmp := rbt.cmp
+
+		rbt.cmp = func(first, second T) int {
+			if result := cmp(first, second); result != 0 {
+				return result
+			}
+
+			return tieBreaker(first, second)
+		}
+	}
+}
+
+// WithMaxCount limits the amount of values of the tree to n, which makes the tree a bounded ordered cache.
+// When a new value is inserted into a full tree, a value is evicted according to evict (see InsertEvict).
+// EvictOldest enables tracking of the insertion order (see WithInsertionOrder).
+// Only insertions evict values: bulk replacements, e.g. ResetFromSorted, may exceed the limit.
+// If n <= 0, the amount of values is not limited.
+func WithMaxCount[T any](n int, evict EvictPolicy) Option[T] {
+	return func(rbt *RBTree[T]) {
+		rbt.maxCount = n
+		rbt.evict = evict
+
+		if evict == EvictOldest {
+			rbt.insertionOrder = true
+		}
+	}
+}
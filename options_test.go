@@ -0,0 +1,154 @@
+package rbtree
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+type event struct {
+	time int
+	name string
+}
+
+func compareEventTimes(first, second event) int {
+	return cmp.Compare(first.time, second.time)
+}
+
+func compareEventNames(first, second event) int {
+	return cmp.Compare(first.name, second.name)
+}
+
+func TestWithTieBreaker(t *testing.T) {
+	t.Parallel()
+
+	events := []event{{2, "b"}, {1, "z"}, {2, "a"}, {1, "z"}, {3, "c"}}
+
+	t.Run("WithTieBreaker: without tie-breaker", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := New(compareEventTimes)
+
+		for _, e := range events {
+			_, _ = rbt.Insert(e)
+		}
+
+		if rbt.Count != 3 {
+			t.Fail()
+		}
+	})
+
+	t.Run("WithTieBreaker: all distinct values are stored", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := New(compareEventTimes, WithTieBreaker(compareEventNames))
+
+		for _, e := range events {
+			_, _ = rbt.Insert(e)
+		}
+
+		var stored []event
+
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			stored = append(stored, rbn.Val)
+		}
+
+		if !rbt.IsValid() || !slices.Equal(stored, []event{{1, "z"}, {2, "a"}, {2, "b"}, {3, "c"}}) {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Find(event{time: 2}); ok {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Find(event{2, "b"}); !ok {
+			t.Fail()
+		}
+	})
+}
+
+func TestWithMaxCount(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		evict    EvictPolicy
+		evicted  []int
+		expected []int
+	}{
+		{"WithMaxCount: EvictMin", EvictMin, []int{1, 2, 3, 4}, []int{5, 6, 7}},
+		{"WithMaxCount: EvictMax", EvictMax, []int{7, 5, 4, 6}, []int{1, 2, 3}},
+		{"WithMaxCount: EvictOldest", EvictOldest, []int{5, 1, 7, 3}, []int{2, 4, 6}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt := NewOrdered(WithMaxCount[int](3, tc.evict))
+
+			var evicted []int
+
+			for _, val := range []int{5, 1, 7, 3, 2, 4, 6} {
+				rbn, inserted, evictedVal, ok := rbt.InsertEvict(val)
+				if ok {
+					evicted = append(evicted, evictedVal)
+				}
+
+				if inserted && rbn.Val != val {
+					t.FailNow()
+				}
+			}
+
+			var stored []int
+
+			for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+				stored = append(stored, rbn.Val)
+			}
+
+			if !rbt.IsValid() || rbt.Count != 3 || !slices.Equal(evicted, tc.evicted) || !slices.Equal(stored, tc.expected) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("WithMaxCount: existing value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithMaxCount[int](2, EvictMin))
+		_, _ = rbt.Insert(1)
+		_, _ = rbt.Insert(2)
+
+		if rbn, inserted, _, ok := rbt.InsertEvict(1); inserted || ok || rbn.Val != 1 || rbt.Count != 2 {
+			t.Fail()
+		}
+	})
+
+	t.Run("WithMaxCount: Insert evicts", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithMaxCount[int](2, EvictOldest))
+
+		for val := range 10 {
+			_, _ = rbt.Insert(val)
+		}
+
+		if !rbt.IsValid() || rbt.Count != 2 || rbt.Min.Val != 8 || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{8, 9}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("WithMaxCount: no limit", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithMaxCount[int](0, EvictMin))
+
+		for val := range 10 {
+			_, _ = rbt.Insert(val)
+		}
+
+		if rbt.Count != 10 || rbt.Clone().maxCount != 0 {
+			t.Fail()
+		}
+	})
+}
@@ -2,7 +2,9 @@ package rbtree
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strings"
+	"sync"
 )
 
 // RBNode is a node of a red-black tree.
@@ -12,6 +14,15 @@ type RBNode[T any] struct {
 	right   *RBNode[T]
 	parent  *RBNode[T]
 	isBlack bool
+	// older and newer link the nodes in the insertion order, if the tree tracks it.
+	older *RBNode[T]
+	newer *RBNode[T]
+	// maxEnd is the node of the subtree with the biggest high endpoint, if the tree is an interval tree.
+	maxEnd *RBNode[T]
+	// size is the number of values of the subtree, counting the duplicates of a multiset.
+	size int
+	// dups is the number of additional copies of the value, if the tree is a multiset.
+	dups int
 }
 
 // Next returns the node with the next closest value and true if this node exists.
@@ -40,83 +51,261 @@ func (rbn *RBNode[T]) Prev() (*RBNode[T], bool) {
 	return rbn.parent, rbn.parent != nil
 }
 
-// clone recursively copies nodes of the red-black tree to a new red-black tree.
+// Sibling returns the other child of the node's parent and true if this node exists.
+// Sibling returns false for the root and for a node whose parent has no other child.
+func (rbn *RBNode[T]) Sibling() (*RBNode[T], bool) {
+	if rbn.parent == nil {
+		return nil, false
+	}
+
+	sibling := rbn.parent.left
+	if sibling == rbn {
+		sibling = rbn.parent.right
+	}
+
+	return sibling, sibling != nil
+}
+
+// Uncle returns the sibling of the node's parent and true if this node exists.
+// Uncle returns false if the node has no grandparent or the grandparent has only one child.
+func (rbn *RBNode[T]) Uncle() (*RBNode[T], bool) {
+	if rbn.parent == nil {
+		return nil, false
+	}
+
+	return rbn.parent.Sibling()
+}
+
+// IsBlack checks if the node is black. A node which is not black is red.
+func (rbn *RBNode[T]) IsBlack() bool {
+	return rbn.isBlack
+}
+
+// nextNode returns the node with the next closest value or nil if this node does not exist.
+func (rbn *RBNode[T]) nextNode() *RBNode[T] {
+	next, _ := rbn.Next()
+
+	return next
+}
+
+// clone copies nodes of the red-black tree to a new red-black tree.
+// The nodes are copied in preorder with an explicit stack, so the goroutine stack is bounded regardless of the depth of the tree.
 func (rbn *RBNode[T]) clone() *RBNode[T] {
-	newNode := &RBNode[T]{
+	root := &RBNode[T]{
 		Val:     rbn.Val,
 		isBlack: rbn.isBlack,
+		size:    rbn.size,
+		dups:    rbn.dups,
 	}
 
-	if rbn.left != nil {
-		newNode.left = rbn.left.clone()
-		newNode.left.parent = newNode
+	for stack := [][2]*RBNode[T]{{rbn, root}}; len(stack) > 0; {
+		original, newNode := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		if original.right != nil {
+			newNode.right = &RBNode[T]{
+				Val:     original.right.Val,
+				parent:  newNode,
+				isBlack: original.right.isBlack,
+				size:    original.right.size,
+				dups:    original.right.dups,
+			}
+			stack = append(stack, [2]*RBNode[T]{original.right, newNode.right})
+		}
+
+		if original.left != nil {
+			newNode.left = &RBNode[T]{
+				Val:     original.left.Val,
+				parent:  newNode,
+				isBlack: original.left.isBlack,
+				size:    original.left.size,
+				dups:    original.left.dups,
+			}
+			stack = append(stack, [2]*RBNode[T]{original.left, newNode.left})
+		}
 	}
 
-	if rbn.right != nil {
-		newNode.right = rbn.right.clone()
-		newNode.right.parent = newNode
+	return root
+}
+
+// firstPostorder returns the first node of the subtree in postorder: the deepest node reached by going left when possible.
+func (rbn *RBNode[T]) firstPostorder() *RBNode[T] {
+	for {
+		switch {
+		case rbn.left != nil:
+			rbn = rbn.left
+		case rbn.right != nil:
+			rbn = rbn.right
+		default:
+			return rbn
+		}
 	}
+}
 
-	return newNode
+// validateLeft checks the link between the node and its left child, which must not be nil.
+func (rbn *RBNode[T]) validateLeft(cmp func(T, T) int) error {
+	if rbn.left.parent != rbn {
+		return fmt.Errorf("%w: left child %v of node %v has a wrong parent", ErrInvalidTree, rbn.left.Val, rbn.Val)
+	}
+
+	if cmp(rbn.Val, rbn.left.Val) <= 0 {
+		return fmt.Errorf("%w: left child %v of node %v is not smaller", ErrInvalidTree, rbn.left.Val, rbn.Val)
+	}
+
+	return nil
 }
 
-// leftSubtreeIsValid checks the validity of the left subtree.
-// leftSubtreeIsValid returns the black height of the tree and true if the tree is valid.
-func (rbn *RBNode[T]) leftSubtreeIsValid(initialBlackHeight *int, currentBlackHeight int, cmp func(T, T) int) (int, bool) {
-	if rbn.left == nil {
-		return currentBlackHeight, true
+// validateRight checks the link between the node and its right child, which must not be nil.
+func (rbn *RBNode[T]) validateRight(cmp func(T, T) int) error {
+	if rbn.right.parent != rbn {
+		return fmt.Errorf("%w: right child %v of node %v has a wrong parent", ErrInvalidTree, rbn.right.Val, rbn.Val)
 	}
 
-	if rbn.left.parent != rbn || cmp(rbn.Val, rbn.left.Val) <= 0 {
-		return 0, false
+	if cmp(rbn.Val, rbn.right.Val) >= 0 {
+		return fmt.Errorf("%w: right child %v of node %v is not bigger", ErrInvalidTree, rbn.right.Val, rbn.Val)
 	}
 
-	return rbn.left.isValid(initialBlackHeight, currentBlackHeight, cmp)
+	return nil
 }
 
-// rightSubtreeIsValid checks the validity of the right subtree.
-// rightSubtreeIsValid returns the black height of the tree and true if the tree is valid.
-func (rbn *RBNode[T]) rightSubtreeIsValid(initialBlackHeight *int, currentBlackHeight int, cmp func(T, T) int) (int, bool) {
-	if rbn.right == nil {
-		return currentBlackHeight, true
+// validate returns the black height of the red-black tree or an error describing the first violation
+// together with the values of the offending node and its parent.
+// The subtree is traversed in depth-first order with an explicit stack, so the goroutine stack is bounded
+// regardless of the depth of the subtree.
+func (rbn *RBNode[T]) validate(cmp func(T, T) int) (int, error) {
+	type frame struct {
+		node *RBNode[T]
+		// pathBlackHeight is the number of black nodes from the root down to the node, including it.
+		pathBlackHeight int
+		// leftBlackHeight is the black height of the left subtree.
+		leftBlackHeight int
+		// state is 0 before the node is checked, 1 after the left subtree, and 2 after the right subtree.
+		state int
 	}
 
-	if rbn.right.parent != rbn || cmp(rbn.Val, rbn.right.Val) >= 0 {
-		return 0, false
+	// firstLeafBlackHeight is the path black height of the first leaf, and result is the black height of the last checked subtree.
+	firstLeafBlackHeight, result := 0, 0
+	stack := []frame{{node: rbn}}
+
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		f := &stack[top]
+		node := f.node
+
+		switch f.state {
+		case 0:
+			if node.isBlack {
+				f.pathBlackHeight++
+			} else if !node.parent.isBlack {
+				return 0, fmt.Errorf("%w: red node %v has red parent %v", ErrInvalidTree, node.Val, node.parent.Val)
+			}
+
+			if node.left == nil && node.right == nil {
+				if firstLeafBlackHeight == 0 {
+					firstLeafBlackHeight = f.pathBlackHeight
+				} else if firstLeafBlackHeight != f.pathBlackHeight {
+					return 0, fmt.Errorf("%w: leaf %v with parent %s has black height %d, but the first leaf has %d",
+						ErrInvalidTree, node.Val, node.parentString(), f.pathBlackHeight, firstLeafBlackHeight)
+				}
+			}
+
+			f.state = 1
+
+			if node.left != nil {
+				if err := node.validateLeft(cmp); err != nil {
+					return 0, err
+				}
+
+				stack = append(stack, frame{node: node.left, pathBlackHeight: f.pathBlackHeight})
+
+				continue
+			}
+
+			result = 0
+
+			fallthrough
+		case 1:
+			f.leftBlackHeight = result
+			f.state = 2
+
+			if node.right != nil {
+				if err := node.validateRight(cmp); err != nil {
+					return 0, err
+				}
+
+				stack = append(stack, frame{node: node.right, pathBlackHeight: f.pathBlackHeight})
+
+				continue
+			}
+
+			result = 0
+
+			fallthrough
+		default:
+			if f.leftBlackHeight != result {
+				// the black heights are reported along the paths from the root
+				return 0, fmt.Errorf("%w: node %v with parent %s has black height %d on the left and %d on the right",
+					ErrInvalidTree, node.Val, node.parentString(), f.pathBlackHeight+f.leftBlackHeight, f.pathBlackHeight+result)
+			}
+
+			result = node.subtreeBlackHeight(f.leftBlackHeight, result)
+			stack = stack[:top]
+		}
 	}
 
-	return rbn.right.isValid(initialBlackHeight, currentBlackHeight, cmp)
+	return result, nil
 }
 
-// isValid returns the black height of the red-black tree and true if the tree is valid.
-func (rbn *RBNode[T]) isValid(initialBlackHeight *int, currentBlackHeight int, cmp func(T, T) int) (int, bool) {
-	if rbn.isBlack {
-		currentBlackHeight++
-	} else if !rbn.parent.isBlack {
-		return 0, false
+// parentString returns the value of the parent of the node formatted with %v, or "none" for the root.
+func (rbn *RBNode[T]) parentString() string {
+	if rbn.parent == nil {
+		return "none"
 	}
 
-	if rbn.left == nil && rbn.right == nil {
-		if *initialBlackHeight == 0 {
-			*initialBlackHeight = currentBlackHeight
+	return fmt.Sprint(rbn.parent.Val)
+}
 
-			return currentBlackHeight, true
-		} else if *initialBlackHeight != currentBlackHeight {
-			return 0, false
+// collectIssues reports the violations of red-black tree rules in the subtree and counts its nodes.
+// collectIssues returns the black height of the subtree.
+func (rbn *RBNode[T]) collectIssues(cmp func(T, T) int, count *int, report func(string, ...any)) int {
+	*count += 1 + rbn.dups
+
+	if !rbn.isBlack && rbn.parent != nil && !rbn.parent.isBlack {
+		report("node %v: red node has red parent %v", rbn.Val, rbn.parent.Val)
+	}
+
+	leftBlackHeight, rightBlackHeight := 0, 0
+
+	switch {
+	case rbn.left == nil:
+	case rbn.left.parent != rbn:
+		report("node %v: left child %v has a wrong parent", rbn.Val, rbn.left.Val)
+	default:
+		if cmp(rbn.Val, rbn.left.Val) <= 0 {
+			report("node %v: left child %v is not smaller", rbn.Val, rbn.left.Val)
 		}
+
+		leftBlackHeight = rbn.left.collectIssues(cmp, count, report)
 	}
 
-	leftBlackHeight, ok := rbn.leftSubtreeIsValid(initialBlackHeight, currentBlackHeight, cmp)
-	if !ok {
-		return 0, false
+	switch {
+	case rbn.right == nil:
+	case rbn.right.parent != rbn:
+		report("node %v: right child %v has a wrong parent", rbn.Val, rbn.right.Val)
+	default:
+		if cmp(rbn.Val, rbn.right.Val) >= 0 {
+			report("node %v: right child %v is not bigger", rbn.Val, rbn.right.Val)
+		}
+
+		rightBlackHeight = rbn.right.collectIssues(cmp, count, report)
 	}
 
-	rightBlackHeight, ok := rbn.rightSubtreeIsValid(initialBlackHeight, currentBlackHeight, cmp)
-	if !ok || leftBlackHeight != rightBlackHeight {
-		return 0, false
+	if leftBlackHeight != rightBlackHeight {
+		report("node %v: black heights of the subtrees differ: %d on the left, %d on the right",
+			rbn.Val, leftBlackHeight, rightBlackHeight)
 	}
 
-	return max(leftBlackHeight, currentBlackHeight), true
+	return rbn.subtreeBlackHeight(leftBlackHeight, rightBlackHeight)
 }
 
 // equalTo recursively checks if both trees have the same structure and nodes.
@@ -125,7 +314,7 @@ func (rbn *RBNode[T]) equalTo(anotherRBN *RBNode[T], cmp func(T, T) int) bool {
 		return false
 	}
 
-	if cmp(rbn.Val, anotherRBN.Val) != 0 || rbn.isBlack != anotherRBN.isBlack {
+	if cmp(rbn.Val, anotherRBN.Val) != 0 || rbn.isBlack != anotherRBN.isBlack || rbn.dups != anotherRBN.dups {
 		return false
 	}
 
@@ -148,73 +337,238 @@ func (rbn *RBNode[T]) equalTo(anotherRBN *RBNode[T], cmp func(T, T) int) bool {
 	return true
 }
 
+// structuralHash hashes the color and the value of the node together with the hashes of its subtrees.
+// The hash of a nil subtree is 0.
+func (rbn *RBNode[T]) structuralHash() uint64 {
+	if rbn == nil {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%t%016x%016x%v", rbn.isBlack, rbn.left.structuralHash(), rbn.right.structuralHash(), rbn.Val)
+
+	return hash.Sum64()
+}
+
 // insert adds a new value to the red-black tree.
 // If the insertion was successful, the newly inserted node and true are returned.
 // Otherwise the existent node and false are returned.
-func (rbn *RBNode[T]) insert(val T, cmp func(T, T) int) (*RBNode[T], bool) {
-	result := cmp(val, rbn.Val)
+// New nodes are taken from the pool if it is not nil (see newNode).
+func (rbn *RBNode[T]) insert(val T, cmp func(T, T) int, pool *sync.Pool) (*RBNode[T], bool) {
+	for {
+		result := cmp(val, rbn.Val)
 
-	switch {
-	case result < 0:
-		if rbn.left == nil {
-			rbn.left = &RBNode[T]{
-				Val:    val,
-				parent: rbn,
+		switch {
+		case result < 0:
+			if rbn.left == nil {
+				rbn.left = newNode(val, rbn, pool)
+
+				return rbn.left, true
 			}
 
-			return rbn.left, true
+			rbn = rbn.left
+		case result > 0:
+			if rbn.right == nil {
+				rbn.right = newNode(val, rbn, pool)
+
+				return rbn.right, true
+			}
+
+			rbn = rbn.right
+		default:
+			return rbn, false
 		}
+	}
+}
 
-		return rbn.left.insert(val, cmp)
-	case result > 0:
-		if rbn.right == nil {
-			rbn.right = &RBNode[T]{
-				Val:    val,
-				parent: rbn,
+// insertFunc works like insert, but searches for the key and adds the value returned by build if the key is missing.
+func (rbn *RBNode[T]) insertFunc(key T, build func() T, cmp func(T, T) int, pool *sync.Pool) (*RBNode[T], bool) {
+	for {
+		result := cmp(key, rbn.Val)
+
+		switch {
+		case result < 0:
+			if rbn.left == nil {
+				rbn.left = newNode(build(), rbn, pool)
+
+				return rbn.left, true
+			}
+
+			rbn = rbn.left
+		case result > 0:
+			if rbn.right == nil {
+				rbn.right = newNode(build(), rbn, pool)
+
+				return rbn.right, true
 			}
 
-			return rbn.right, true
+			rbn = rbn.right
+		default:
+			return rbn, false
+		}
+	}
+}
+
+// newNode returns a red node with the value and the parent. The node is taken from the pool
+// if the pool is not nil and not empty, or allocated otherwise.
+func newNode[T any](val T, parent *RBNode[T], pool *sync.Pool) *RBNode[T] {
+	if pool != nil {
+		if rbn, ok := pool.Get().(*RBNode[T]); ok {
+			rbn.Val = val
+			rbn.parent = parent
+
+			return rbn
 		}
+	}
 
-		return rbn.right.insert(val, cmp)
-	default:
-		return rbn, false
+	return &RBNode[T]{
+		Val:    val,
+		parent: parent,
 	}
 }
 
 // recString makes a multi-string depiction of the tree.
 // The tree is aligned left-to-right with the root on the left side of the depiction.
-func (rbn *RBNode[T]) recString(result *string, counter int) {
+// Every level of the tree is indented by width spaces after a separating space, which is omitted with the indentation
+// if width is 0, and the values are formatted with format.
+func (rbn *RBNode[T]) recString(result *string, counter int, width int, format func(T) string) {
 	if rbn.right != nil {
-		rbn.right.recString(result, counter+1)
+		rbn.right.recString(result, counter+1, width, format)
+	}
+
+	if width > 0 {
+		*result += strings.Repeat(" ", counter*width) + " "
 	}
 
-	*result += fmt.Sprintln(strings.Repeat(" ", counter), rbn.Val)
+	*result += format(rbn.Val) + "\n"
 
 	if rbn.left != nil {
-		rbn.left.recString(result, counter+1)
+		rbn.left.recString(result, counter+1, width, format)
 	}
 }
 
+// recPrettyPrint writes the lines of the subtree for PrettyPrint. The line of the node starts with
+// prefix and branch, and the lines of its children start with prefix followed by the continuation of the branch.
+func (rbn *RBNode[T]) recPrettyPrint(sb *strings.Builder, prefix, branch string) {
+	if rbn == nil {
+		sb.WriteString(prefix + branch + "nil\n")
+
+		return
+	}
+
+	color := "(R)"
+	if rbn.isBlack {
+		color = "(B)"
+	}
+
+	fmt.Fprintf(sb, "%s%s%v %s\n", prefix, branch, rbn.Val, color)
+
+	if rbn.left == nil && rbn.right == nil {
+		return
+	}
+
+	switch branch {
+	case "├── ":
+		prefix += "│   "
+	case "└── ":
+		prefix += "    "
+	}
+
+	rbn.left.recPrettyPrint(sb, prefix, "├── ")
+	rbn.right.recPrettyPrint(sb, prefix, "└── ")
+}
+
+// recDOT writes the DOT statements of the subtree and its nil leaves, numbering them in preorder from id,
+// and returns the name of the subtree root. A nil node is depicted as a leaf.
+func (rbn *RBNode[T]) recDOT(sb *strings.Builder, id *int) string {
+	name := fmt.Sprintf("n%d", *id)
+	*id++
+
+	if rbn == nil {
+		fmt.Fprintf(sb, "\t%s [label=\"\", shape=box, width=0.1, height=0.1, fillcolor=black];\n", name)
+
+		return name
+	}
+
+	color := "red"
+	if rbn.isBlack {
+		color = "black"
+	}
+
+	fmt.Fprintf(sb, "\t%s [label=%q, fillcolor=%s];\n", name, fmt.Sprint(rbn.Val), color)
+	fmt.Fprintf(sb, "\t%s -> %s;\n", name, rbn.left.recDOT(sb, id))
+	fmt.Fprintf(sb, "\t%s -> %s;\n", name, rbn.right.recDOT(sb, id))
+
+	return name
+}
+
 // find returns the node pointer and true if a node with particular value was found in the red-black tree.
 func (rbn *RBNode[T]) find(val T, cmp func(T, T) int) (*RBNode[T], bool) {
+	for rbn != nil {
+		result := cmp(val, rbn.Val)
+
+		switch {
+		case result < 0:
+			rbn = rbn.left
+		case result > 0:
+			rbn = rbn.right
+		default:
+			return rbn, true
+		}
+	}
+
+	return nil, false
+}
+
+// ceiling returns the node with the smallest value greater than or equal to val or nil if there is no such node.
+func (rbn *RBNode[T]) ceiling(val T, cmp func(T, T) int) *RBNode[T] {
 	result := cmp(val, rbn.Val)
 
 	switch {
 	case result < 0:
 		if rbn.left == nil {
-			return nil, false
+			return rbn
+		}
+
+		if node := rbn.left.ceiling(val, cmp); node != nil {
+			return node
 		}
 
-		return rbn.left.find(val, cmp)
+		return rbn
 	case result > 0:
 		if rbn.right == nil {
-			return nil, false
+			return nil
 		}
 
-		return rbn.right.find(val, cmp)
+		return rbn.right.ceiling(val, cmp)
 	default:
-		return rbn, true
+		return rbn
+	}
+}
+
+// floor returns the node with the biggest value less than or equal to val or nil if there is no such node.
+func (rbn *RBNode[T]) floor(val T, cmp func(T, T) int) *RBNode[T] {
+	result := cmp(val, rbn.Val)
+
+	switch {
+	case result < 0:
+		if rbn.left == nil {
+			return nil
+		}
+
+		return rbn.left.floor(val, cmp)
+	case result > 0:
+		if rbn.right == nil {
+			return rbn
+		}
+
+		if node := rbn.right.floor(val, cmp); node != nil {
+			return node
+		}
+
+		return rbn
+	default:
+		return rbn
 	}
 }
 
@@ -240,3 +594,143 @@ func (rbn *RBNode[T]) rightmost() *RBNode[T] {
 func isBlack[T any](rbn *RBNode[T]) bool {
 	return rbn == nil || rbn.isBlack
 }
+
+// height returns the number of nodes on the longest path from the node down to a leaf. The height of an empty subtree is 0.
+func (rbn *RBNode[T]) height() int {
+	if rbn == nil {
+		return 0
+	}
+
+	return max(rbn.left.height(), rbn.right.height()) + 1
+}
+
+// blackHeight returns the number of black nodes on the leftmost path from the node down to a leaf, including the node itself.
+// In a valid tree all paths contain the same number of black nodes. The black height of an empty subtree is 0.
+func (rbn *RBNode[T]) blackHeight() int {
+	height := 0
+
+	for ; rbn != nil; rbn = rbn.left {
+		if rbn.isBlack {
+			height++
+		}
+	}
+
+	return height
+}
+
+// heightBalanced returns the height of the subtree and true if the heights of the subtrees of every node
+// differ by no more than maxSkew. The height of an empty subtree is 0.
+func (rbn *RBNode[T]) heightBalanced(maxSkew int) (int, bool) {
+	if rbn == nil {
+		return 0, true
+	}
+
+	leftHeight, ok := rbn.left.heightBalanced(maxSkew)
+	if !ok {
+		return 0, false
+	}
+
+	rightHeight, ok := rbn.right.heightBalanced(maxSkew)
+	if !ok || max(leftHeight-rightHeight, rightHeight-leftHeight) > maxSkew {
+		return 0, false
+	}
+
+	return max(leftHeight, rightHeight) + 1, true
+}
+
+// blackHeights counts the black heights of the nodes of the subtree in the histogram, unless it is nil.
+// blackHeights returns the black height of the subtree, including the node itself. The black height of an empty subtree is 0.
+func (rbn *RBNode[T]) blackHeights(histogram map[int]int) int {
+	if rbn == nil {
+		return 0
+	}
+
+	left, right := rbn.left.blackHeights(histogram), rbn.right.blackHeights(histogram)
+
+	if histogram != nil {
+		histogram[max(left, right)]++
+	}
+
+	return rbn.subtreeBlackHeight(left, right)
+}
+
+// subtreeBlackHeight returns the black height of the subtree of the node, including the node itself, from the black heights
+// of its left and right subtrees. They are equal in a valid tree; otherwise the maximum is taken.
+func (rbn *RBNode[T]) subtreeBlackHeight(left, right int) int {
+	if rbn.isBlack {
+		return max(left, right) + 1
+	}
+
+	return max(left, right)
+}
+
+// blackHeightSets returns the sets of black heights, which the subtree can have in valid colorings
+// with a black root and with a red root, and stores them for every node in heights.
+// The bit h of a set is 1 if the black height h is possible. The black height of an empty subtree is 0.
+func (rbn *RBNode[T]) blackHeightSets(heights map[*RBNode[T]][2]uint64) (uint64, uint64) {
+	if rbn == nil {
+		return 1, 0
+	}
+
+	leftBlack, leftRed := rbn.left.blackHeightSets(heights)
+	rightBlack, rightRed := rbn.right.blackHeightSets(heights)
+
+	black := ((leftBlack | leftRed) & (rightBlack | rightRed)) << 1
+	red := leftBlack & rightBlack
+	heights[rbn] = [2]uint64{black, red}
+
+	return black, red
+}
+
+// Size returns the number of values of the subtree of the node, including the node itself.
+// In a multiset every value is counted as many times as it occurs, otherwise it is the number of nodes.
+// The sizes of the subtrees are maintained by every mutation of the tree, so Size takes O(1) time.
+// Size returns 0 for a nil node.
+func (rbn *RBNode[T]) Size() int {
+	if rbn == nil {
+		return 0
+	}
+
+	return rbn.size
+}
+
+// Multiplicity returns the number of occurrences of the value of the node in the tree.
+// It is always 1 unless the tree is a multiset (see NewMultiset). Multiplicity returns 0 for a nil node.
+func (rbn *RBNode[T]) Multiplicity() int {
+	if rbn == nil {
+		return 0
+	}
+
+	return 1 + rbn.dups
+}
+
+// yieldCopies yields the value of the node as many times as it occurs in the tree.
+// yieldCopies returns false if the iteration was stopped.
+func (rbn *RBNode[T]) yieldCopies(yield func(T) bool) bool {
+	for range 1 + rbn.dups {
+		if !yield(rbn.Val) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describe counts the red, black and leaf nodes of the subtree in stats and returns the height of the subtree.
+func (rbn *RBNode[T]) describe(stats *TreeStats[T]) int {
+	if rbn == nil {
+		return 0
+	}
+
+	if rbn.isBlack {
+		stats.Black++
+	} else {
+		stats.Red++
+	}
+
+	if rbn.left == nil && rbn.right == nil {
+		stats.Leaves++
+	}
+
+	return max(rbn.left.describe(stats), rbn.right.describe(stats)) + 1
+}
@@ -3,6 +3,34 @@ package rbtree
 
 import (
 	"cmp"
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNilNode is returned when a nil node is passed instead of a node of the tree.
+	ErrNilNode = errors.New("rbtree: nil node")
+	// ErrNoLeftChild is returned when an operation requires a node with a left child.
+	ErrNoLeftChild = errors.New("rbtree: node has no left child")
+	// ErrNoRightChild is returned when an operation requires a node with a right child.
+	ErrNoRightChild = errors.New("rbtree: node has no right child")
+	// ErrNotSorted is returned when values are expected in strictly ascending order, but they are not.
+	ErrNotSorted = errors.New("rbtree: values are not in ascending order")
+	// ErrBlackHeight is returned when a subtree can't be colored to match the black height of the rest of the tree.
+	ErrBlackHeight = errors.New("rbtree: subtree can't match the black height of the tree")
+	// ErrNoCmp is returned when an operation requires the comparison function, but the tree was created without New.
+	ErrNoCmp = errors.New("rbtree: no comparison function")
+	// ErrCorrupted is returned when encoded values are inconsistent.
+	ErrCorrupted = errors.New("rbtree: corrupted encoding")
+	// ErrInvalidTree is wrapped by the errors returned by Validate.
+	ErrInvalidTree = errors.New("rbtree: invalid red-black tree")
+	// ErrFrozen is returned or used as a panic value when a frozen tree is mutated.
+	ErrFrozen = errors.New("rbtree: mutation of a frozen tree")
 )
 
 // RBTree is a red-black tree. It contains the size and pointers to the first and the last nodes.
@@ -14,8 +42,34 @@ type RBTree[T any] struct {
 	Min *RBNode[T]
 	// Left is a pointer to the node with the biggest value of the tree.
 	Max *RBNode[T]
-	// Count is an amount of nodes in the tree.
+	// Count is an amount of values in the tree, which is the amount of nodes unless the tree is a multiset.
 	Count int
+
+	// oldest and newest are the ends of the insertion order list, which is maintained if insertionOrder is set.
+	oldest         *RBNode[T]
+	newest         *RBNode[T]
+	insertionOrder bool
+	// cmpEnd compares the high endpoints of two intervals, if the tree is an interval tree.
+	cmpEnd func(T, T) int
+	frozen bool
+	// deferUpdates suspends the maintenance of the augmented data while the tree is being built;
+	// the data of the whole tree is computed with updateSubtree afterwards.
+	deferUpdates bool
+	// maxCount limits the amount of values, if it is positive. Values are evicted according to evict.
+	maxCount int
+	evict    EvictPolicy
+	// counters measure the rebalancing work, if they are set.
+	counters *rebalanceCounters
+	// pool keeps the removed nodes for reuse by insertions, if it is set.
+	pool *sync.Pool
+	// multiset allows equal values, which are counted in the dups field of their node.
+	multiset bool
+}
+
+// rebalanceCounters count the rotations and the color flips performed while rebalancing the tree.
+type rebalanceCounters struct {
+	rotations  int
+	colorFlips int
 }
 
 // New returns an empty red-black tree.
@@ -27,205 +81,1657 @@ type RBTree[T any] struct {
 //   - result > 0, if first value is bigger;
 //   - result == 0, if both values are equal.
 //
-// For ordered primitive types, use NewOrdered.
-func New[T any](cmp func(T, T) int) *RBTree[T] {
-	return &RBTree[T]{
+// For ordered primitive types, use NewOrdered. The behavior of the tree can be adjusted with options.
+func New[T any](cmp func(T, T) int, opts ...Option[T]) *RBTree[T] {
+	rbt := &RBTree[T]{
 		cmp: cmp,
 	}
-}
 
-// NewOrdered returns an empty red-black tree for primitive types ([cmp.Ordered]).
-func NewOrdered[T cmp.Ordered]() *RBTree[T] {
-	return New(cmp.Compare[T])
+	for _, opt := range opts {
+		opt(rbt)
+	}
+
+	return rbt
+}
+
+// NewOrdered returns an empty red-black tree for primitive types ([cmp.Ordered]).
+func NewOrdered[T cmp.Ordered](opts ...Option[T]) *RBTree[T] {
+	return New(cmp.Compare[T], opts...)
+}
+
+// NewReverse returns an empty red-black tree for primitive types ([cmp.Ordered]) sorted in descending order.
+// The comparison function is reversed, so Min is the node with the biggest value and All yields the values in descending order.
+func NewReverse[T cmp.Ordered](opts ...Option[T]) *RBTree[T] {
+	return New(func(a, b T) int {
+		return cmp.Compare(b, a)
+	}, opts...)
+}
+
+// NewPooled returns an empty red-black tree like New, which reuses the nodes removed by deletions
+// for later insertions instead of allocating new ones. The removed nodes are reset and kept in a [sync.Pool],
+// which is shared with the copies of the tree made by Clone and set operations.
+// Since a removed node may be reused for any other value, nodes must not be used after their values are deleted.
+// Deletion removes only the node of the deleted value, so the nodes of the other values keep their values.
+func NewPooled[T any](cmp func(T, T) int, opts ...Option[T]) *RBTree[T] {
+	rbt := New(cmp, opts...)
+	rbt.pool = &sync.Pool{}
+
+	return rbt
+}
+
+// NewMultiset returns an empty red-black tree like New, which allows equal values.
+// Every value is stored in a single node with its number of occurrences (see RBNode.Multiplicity):
+// Insert of an existing value increments it, and Delete decrements it and deletes the node when it reaches zero.
+// Count, Len and the order statistics (e.g. Select and Rank) count every occurrence, and All, Backward, Range
+// and ToSlice yield a value as many times as it occurs. The insertion order tracks the first occurrence of a value.
+// Set operations (e.g. Union) and Nodes treat the tree as a set of distinct values.
+func NewMultiset[T any](cmp func(T, T) int, opts ...Option[T]) *RBTree[T] {
+	rbt := New(cmp, opts...)
+	rbt.multiset = true
+
+	return rbt
+}
+
+// Clone copies the red-black tree to a new red-black tree with the same values and structure.
+// The insertion order is preserved as well if it is tracked.
+// Clone returns a new red-black tree.
+func (rbt *RBTree[T]) Clone() *RBTree[T] {
+	return rbt.cloneSubtree(rbt.root)
+}
+
+// Subtree copies the subtree of the node to a new standalone red-black tree.
+// The root of the copy is recolored to black, which keeps the copy valid:
+// all paths of a subtree of a valid tree contain the same number of black nodes.
+// The node must belong to the tree. Subtree returns an empty tree if the node is nil.
+func (rbt *RBTree[T]) Subtree(rbn *RBNode[T]) *RBTree[T] {
+	tree := rbt.cloneSubtree(rbn)
+
+	if tree.root != nil {
+		tree.root.isBlack = true
+	}
+
+	return tree
+}
+
+// cloneSubtree copies the subtree of the node to a new red-black tree with the same options.
+// The insertion order of the copied values is preserved if it is tracked.
+func (rbt *RBTree[T]) cloneSubtree(rbn *RBNode[T]) *RBTree[T] {
+	tree := rbt.emptyCopy()
+
+	if rbn == nil {
+		return tree
+	}
+
+	tree.root = rbn.clone()
+	tree.Min = tree.root.leftmost()
+	tree.Max = tree.root.rightmost()
+	tree.updateSubtree(tree.root)
+
+	var clones map[*RBNode[T]]*RBNode[T]
+
+	if rbt.insertionOrder {
+		clones = make(map[*RBNode[T]]*RBNode[T])
+	}
+
+	for original, clone := rbn.leftmost(), tree.Min; clone != nil; original, clone = original.nextNode(), clone.nextNode() {
+		if clones != nil {
+			clones[original] = clone
+		}
+
+		tree.Count += clone.Multiplicity()
+	}
+
+	for original := rbt.oldest; clones != nil && original != nil; original = original.newer {
+		if clone, ok := clones[original]; ok {
+			tree.pushNewest(clone)
+		}
+	}
+
+	return tree
+}
+
+// emptyCopy returns an empty red-black tree with the same comparison function and options.
+func (rbt *RBTree[T]) emptyCopy() *RBTree[T] {
+	return &RBTree[T]{
+		cmp:            rbt.cmp,
+		insertionOrder: rbt.insertionOrder,
+		cmpEnd:         rbt.cmpEnd,
+		maxCount:       rbt.maxCount,
+		evict:          rbt.evict,
+		pool:           rbt.pool,
+		multiset:       rbt.multiset,
+	}
+}
+
+// Freeze makes the tree read-only. Reading methods keep working normally, while mutating methods
+// (e.g. Insert and Delete) panic with ErrFrozen, or return it if they return an error.
+// It guards snapshots shared between goroutines against accidental mutation. A clone of a frozen tree is mutable.
+func (rbt *RBTree[T]) Freeze() {
+	rbt.frozen = true
+}
+
+// IsFrozen checks if the tree is read-only (see Freeze).
+func (rbt *RBTree[T]) IsFrozen() bool {
+	return rbt.frozen
+}
+
+// checkMutable panics with ErrFrozen if the tree is frozen.
+func (rbt *RBTree[T]) checkMutable() {
+	if rbt.frozen {
+		panic(ErrFrozen)
+	}
+}
+
+// Transaction runs fn on the tree and restores the tree to its state before the call if fn returns an error.
+// The error returned by fn is passed through.
+// Before running fn the tree is copied with Clone, which takes O(n) time and memory.
+// If the tree is restored, the nodes obtained before or during the transaction no longer belong to the tree.
+// Transaction does not synchronize access to the tree: concurrent access during a transaction is the caller's responsibility.
+// Transaction returns ErrFrozen without running fn if the tree is frozen.
+func (rbt *RBTree[T]) Transaction(fn func(*RBTree[T]) error) error {
+	if rbt.frozen {
+		return ErrFrozen
+	}
+
+	snapshot := rbt.Clone()
+
+	if err := fn(rbt); err != nil {
+		*rbt = *snapshot
+
+		return err
+	}
+
+	return nil
+}
+
+// IsValid checks if the tree is a valid red-black tree.
+func (rbt *RBTree[T]) IsValid() bool {
+	return rbt.Validate() == nil
+}
+
+// Validate checks if the tree is a valid red-black tree like IsValid and returns an error describing the first violation.
+// The error wraps [ErrInvalidTree]. For violations of the red-black rules (a red node with a red parent, different
+// black heights) and of the order, the error contains the values of the offending node and its parent.
+// To collect all violations, use ValidateVerbose.
+func (rbt *RBTree[T]) Validate() error {
+	if rbt.cmp == nil {
+		return fmt.Errorf("%w: no comparison function", ErrInvalidTree)
+	}
+
+	if rbt.root == nil {
+		if rbt.Min != nil || rbt.Max != nil || rbt.Count != 0 {
+			return fmt.Errorf("%w: empty tree has Min, Max or Count set", ErrInvalidTree)
+		}
+
+		return nil
+	}
+
+	if rbt.root.parent != nil {
+		return fmt.Errorf("%w: root %v has a parent", ErrInvalidTree, rbt.root.Val)
+	}
+
+	if !rbt.root.isBlack {
+		return fmt.Errorf("%w: root %v is red", ErrInvalidTree, rbt.root.Val)
+	}
+
+	count := 0
+
+	if _, err := rbt.root.validate(rbt.cmp); err != nil {
+		return err
+	}
+
+	if rbt.Min != rbt.root.leftmost() {
+		return fmt.Errorf("%w: Min does not point to the node with the smallest value", ErrInvalidTree)
+	}
+
+	if rbt.Max != rbt.root.rightmost() {
+		return fmt.Errorf("%w: Max does not point to the node with the biggest value", ErrInvalidTree)
+	}
+
+	for i, ok := rbt.Min, true; ok; i, ok = i.Next() {
+		if i.dups != 0 && !rbt.multiset {
+			return fmt.Errorf("%w: node %v has duplicates, but the tree is not a multiset", ErrInvalidTree, i.Val)
+		}
+
+		count += i.Multiplicity()
+	}
+
+	if count != rbt.Count {
+		return fmt.Errorf("%w: Count is %d, but the tree has %d nodes", ErrInvalidTree, rbt.Count, count)
+	}
+
+	if !rbt.insertionOrderIsValid() {
+		return fmt.Errorf("%w: insertion order list is inconsistent", ErrInvalidTree)
+	}
+
+	if !rbt.augmentationIsValid(rbt.root) {
+		return fmt.Errorf("%w: augmented data is stale", ErrInvalidTree)
+	}
+
+	return nil
+}
+
+// augmentationIsValid checks if the augmented data of every node of the subtree is up to date.
+// The nodes are visited with an explicit stack, so the goroutine stack is bounded regardless of the depth of the subtree.
+func (rbt *RBTree[T]) augmentationIsValid(rbn *RBNode[T]) bool {
+	for stack := []*RBNode[T]{rbn}; len(stack) > 0; {
+		rbn = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if rbn == nil {
+			continue
+		}
+
+		if !rbt.nodeAugmentationIsValid(rbn) {
+			return false
+		}
+
+		stack = append(stack, rbn.right, rbn.left)
+	}
+
+	return true
+}
+
+// nodeAugmentationIsValid checks if the augmented data of the node is up to date, provided that its children are.
+func (rbt *RBTree[T]) nodeAugmentationIsValid(rbn *RBNode[T]) bool {
+	if rbn.size != rbn.Multiplicity()+rbn.left.Size()+rbn.right.Size() {
+		return false
+	}
+
+	if rbt.cmpEnd == nil {
+		return true
+	}
+
+	maxEnd := rbn
+
+	for _, child := range []*RBNode[T]{rbn.left, rbn.right} {
+		if child == nil {
+			continue
+		}
+
+		if child.maxEnd == nil {
+			return false
+		}
+
+		if rbt.cmpEnd(child.maxEnd.Val, maxEnd.Val) > 0 {
+			maxEnd = child.maxEnd
+		}
+	}
+
+	return rbn.maxEnd != nil && rbt.cmpEnd(rbn.maxEnd.Val, maxEnd.Val) == 0
+}
+
+// maxValidationIssues limits the number of problems reported by ValidateVerbose.
+const maxValidationIssues = 100
+
+// ValidateVerbose checks the tree like IsValid, but instead of stopping at the first problem,
+// it collects human-readable descriptions of all violated rules, naming the offending values.
+// Subtrees with wrong parent pointers are reported, but not inspected. At most 100 problems are reported.
+// ValidateVerbose returns an empty slice if the tree is valid.
+func (rbt *RBTree[T]) ValidateVerbose() []string {
+	issues := []string{}
+	report := func(format string, args ...any) {
+		if len(issues) < maxValidationIssues {
+			issues = append(issues, fmt.Sprintf(format, args...))
+		}
+	}
+
+	if rbt.cmp == nil {
+		report("tree: no comparison function")
+
+		return issues
+	}
+
+	count := 0
+
+	if rbt.root != nil {
+		if rbt.root.parent != nil {
+			report("root %v: root has a parent", rbt.root.Val)
+		}
+
+		if !rbt.root.isBlack {
+			report("root %v: root is red", rbt.root.Val)
+		}
+
+		rbt.root.collectIssues(rbt.cmp, &count, report)
+	}
+
+	if rbt.root != nil && rbt.Min != rbt.root.leftmost() || rbt.root == nil && rbt.Min != nil {
+		report("tree: Min does not point to the node with the smallest value")
+	}
+
+	if rbt.root != nil && rbt.Max != rbt.root.rightmost() || rbt.root == nil && rbt.Max != nil {
+		report("tree: Max does not point to the node with the biggest value")
+	}
+
+	if count != rbt.Count {
+		report("tree: Count is %d, but the tree has %d nodes", rbt.Count, count)
+	}
+
+	if !rbt.insertionOrderIsValid() {
+		report("tree: insertion order list is inconsistent")
+	}
+
+	if !rbt.augmentationIsValid(rbt.root) {
+		report("tree: augmented data is stale")
+	}
+
+	return issues
+}
+
+// IsTree checks if every node is reachable from the root exactly once,
+// i.e. the nodes form a tree rather than a graph with shared subtrees or cycles.
+// IsTree keeps track of visited nodes, so it terminates even on such malformed trees, e.g. after manual node manipulation.
+func (rbt *RBTree[T]) IsTree() bool {
+	if rbt.root == nil {
+		return true
+	}
+
+	visited := make(map[*RBNode[T]]struct{}, rbt.Count)
+	stack := []*RBNode[T]{rbt.root}
+
+	for len(stack) > 0 {
+		rbn := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, ok := visited[rbn]; ok {
+			return false
+		}
+
+		visited[rbn] = struct{}{}
+
+		if rbn.left != nil {
+			stack = append(stack, rbn.left)
+		}
+
+		if rbn.right != nil {
+			stack = append(stack, rbn.right)
+		}
+	}
+
+	return true
+}
+
+// EqualValues checks if both trees contain the same values regardless of their structure and colors.
+// The numbers of values are compared first, then both trees are walked in order until the first mismatch.
+// In a multiset the numbers of occurrences of every value must match as well.
+func (rbt *RBTree[T]) EqualValues(other *RBTree[T]) bool {
+	if other == nil || rbt.Count != other.Count {
+		return false
+	}
+
+	for rbn, otherRBN := rbt.Min, other.Min; rbn != nil; rbn, otherRBN = rbn.nextNode(), otherRBN.nextNode() {
+		if otherRBN == nil || rbt.cmp(rbn.Val, otherRBN.Val) != 0 || rbn.Multiplicity() != otherRBN.Multiplicity() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CanonicalizeColors recolors the tree to the canonical coloring of its shape, so that trees with the same values
+// and shape become equal according to EqualTo regardless of the code paths that built them.
+// Among the valid colorings of the shape, the one with the biggest black height is chosen,
+// and every node is black unless its black height requires it to be red. The result depends only on the shape,
+// so recoloring a canonically colored tree changes nothing. If the shape has no valid coloring
+// (which is possible only after RotateLeft or RotateRight), the tree is left unchanged.
+// CanonicalizeColors takes O(n) time and memory. It panics if the tree is frozen.
+func (rbt *RBTree[T]) CanonicalizeColors() {
+	rbt.checkMutable()
+
+	if rbt.root == nil {
+		return
+	}
+
+	heights := make(map[*RBNode[T]][2]uint64, rbt.Count)
+
+	black, _ := rbt.root.blackHeightSets(heights)
+	if black == 0 {
+		return
+	}
+
+	rbt.paintCanonical(rbt.root, bits.Len64(black)-1, heights)
+}
+
+// paintCanonical paints the node black if its subtree can have the black height with a black root, or red otherwise,
+// and paints the subtrees accordingly.
+func (rbt *RBTree[T]) paintCanonical(rbn *RBNode[T], height int, heights map[*RBNode[T]][2]uint64) {
+	if rbn == nil {
+		return
+	}
+
+	black := heights[rbn][0]&(1<<height) != 0
+	rbt.paint(rbn, black)
+
+	if black {
+		height--
+	}
+
+	rbt.paintCanonical(rbn.left, height, heights)
+	rbt.paintCanonical(rbn.right, height, heights)
+}
+
+// StructuralHash hashes the values, the colors and the shape of the tree in a single post-order traversal.
+// Trees with identical structure (see EqualTo) have identical hashes, provided that equal values are formatted
+// identically with %v, since the values are hashed by their default format. Different trees may collide,
+// so equal hashes must be confirmed with EqualTo, while different hashes prove the trees are different.
+func (rbt *RBTree[T]) StructuralHash() uint64 {
+	return rbt.root.structuralHash()
+}
+
+// IndependentFrom checks if the tree shares no nodes with another tree, e.g. if a clone is fully independent of the original.
+// Together with EqualTo, it verifies that a clone is an equal, but deep copy. An empty tree is independent of any tree.
+// Both trees are traversed, which takes O(n + m) time and O(n) memory.
+func (rbt *RBTree[T]) IndependentFrom(other *RBTree[T]) bool {
+	nodes := rbt.reachableNodes()
+
+	for rbn := range other.reachableNodes() {
+		if _, ok := nodes[rbn]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reachableNodes returns the set of nodes reachable from the root, including Min and Max.
+// Every node is visited once, so it terminates even on malformed trees.
+func (rbt *RBTree[T]) reachableNodes() map[*RBNode[T]]struct{} {
+	visited := make(map[*RBNode[T]]struct{}, rbt.Count)
+	stack := []*RBNode[T]{rbt.root, rbt.Min, rbt.Max}
+
+	for len(stack) > 0 {
+		rbn := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if rbn == nil {
+			continue
+		}
+
+		if _, ok := visited[rbn]; ok {
+			continue
+		}
+
+		visited[rbn] = struct{}{}
+		stack = append(stack, rbn.left, rbn.right)
+	}
+
+	return visited
+}
+
+// insertionOrderIsValid checks if the insertion order list is consistent and contains every node of the tree.
+func (rbt *RBTree[T]) insertionOrderIsValid() bool {
+	if !rbt.insertionOrder {
+		return rbt.oldest == nil && rbt.newest == nil
+	}
+
+	count, nodes := 0, rbt.Count
+
+	if rbt.multiset {
+		nodes = 0
+
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			nodes++
+		}
+	}
+
+	var prev *RBNode[T]
+
+	for rbn := rbt.oldest; rbn != nil; rbn = rbn.newer {
+		if rbn.older != prev || count == nodes {
+			return false
+		}
+
+		prev = rbn
+		count++
+	}
+
+	return prev == rbt.newest && count == nodes
+}
+
+// EqualTo checks if both trees have the same structure and nodes.
+func (rbt *RBTree[T]) EqualTo(anotherRBT *RBTree[T]) bool {
+	if anotherRBT == nil {
+		return false
+	}
+
+	if rbt.root == nil && anotherRBT.root == nil {
+		return true
+	}
+
+	if rbt.root == nil || anotherRBT.root == nil {
+		return false
+	}
+
+	if rbt.Count != anotherRBT.Count {
+		return false
+	}
+
+	return rbt.root.equalTo(anotherRBT.root, rbt.cmp)
+}
+
+// Insert adds a new value to the red-black tree and fixes the tree afterwards if necessary.
+// If the insertion was successful, the newly inserted node and true are returned.
+// Otherwise the existent node and false are returned. Insert panics if the tree is frozen.
+// If the tree is limited with WithMaxCount, Insert may evict a value (see InsertEvict).
+// In a multiset (see NewMultiset), inserting an existing value adds an occurrence and returns its node and true.
+func (rbt *RBTree[T]) Insert(val T) (*RBNode[T], bool) {
+	rbn, ok, _, _ := rbt.InsertEvict(val)
+
+	return rbn, ok
+}
+
+// InsertEvict adds a new value like Insert and also returns the value evicted to keep the tree within
+// the limit set with WithMaxCount together with true, or an empty value and false if nothing was evicted.
+// The value is evicted before the insertion, so the returned node stays valid. If the new value itself
+// would be evicted by EvictMin or EvictMax, it is not inserted: nil, false, the new value and true are returned.
+// InsertEvict panics if the tree is frozen.
+func (rbt *RBTree[T]) InsertEvict(val T) (*RBNode[T], bool, T, bool) {
+	rbt.checkMutable()
+
+	var evicted T
+
+	if rbt.maxCount > 0 && rbt.Count >= rbt.maxCount {
+		if rbn, ok := rbt.Find(val); ok && !rbt.multiset {
+			return rbn, false, evicted, false
+		}
+
+		switch {
+		case rbt.evict == EvictMin && rbt.cmp(val, rbt.Min.Val) < 0,
+			rbt.evict == EvictMax && rbt.cmp(val, rbt.Max.Val) > 0:
+			return nil, false, val, true
+		case rbt.evict == EvictMin:
+			evicted = rbt.deleteNode(rbt.Min)
+		case rbt.evict == EvictMax:
+			evicted = rbt.deleteNode(rbt.Max)
+		default:
+			evicted = rbt.deleteNode(rbt.oldest)
+		}
+
+		rbn, _ := rbt.insert(val)
+
+		return rbn, true, evicted, true
+	}
+
+	rbn, ok := rbt.insert(val)
+
+	return rbn, ok, evicted, false
+}
+
+// insert adds a new value to the red-black tree and fixes the tree afterwards if necessary.
+func (rbt *RBTree[T]) insert(val T) (*RBNode[T], bool) {
+	if rbt.root == nil {
+		return rbt.insertRoot(val), true
+	}
+
+	insertedNode, ok := rbt.root.insert(val, rbt.cmp, rbt.pool)
+	if !ok && rbt.multiset {
+		insertedNode.dups++
+		rbt.Count++
+		rbt.updatePath(insertedNode)
+
+		return insertedNode, true
+	}
+
+	if !ok {
+		return insertedNode, false
+	}
+
+	rbt.fixInsert(insertedNode)
+
+	return insertedNode, true
+}
+
+// insertRoot adds a value to the empty tree and returns the new root.
+func (rbt *RBTree[T]) insertRoot(val T) *RBNode[T] {
+	rbt.root = newNode(val, nil, rbt.pool)
+	rbt.root.isBlack = true
+
+	rbt.Min = rbt.root
+	rbt.Max = rbt.root
+
+	rbt.update(rbt.root)
+	rbt.pushNewest(rbt.root)
+	rbt.Count++
+
+	return rbt.root
+}
+
+// fixInsert updates the tree after the new node was linked as a leaf and fixes the tree if necessary.
+func (rbt *RBTree[T]) fixInsert(insertedNode *RBNode[T]) {
+	rbt.pushNewest(insertedNode)
+
+	if rbt.cmp(insertedNode.Val, rbt.Min.Val) < 0 {
+		rbt.Min = insertedNode
+	} else if rbt.cmp(insertedNode.Val, rbt.Max.Val) > 0 {
+		rbt.Max = insertedNode
+	}
+
+	rbt.updatePath(insertedNode)
+
+	if !insertedNode.parent.isBlack {
+		rbt.solveDoubleRed(insertedNode.parent)
+	}
+	rbt.Count++
+}
+
+// GetOrInsert returns the node with the value and false if the value exists in the tree,
+// or adds the value and returns the new node and true otherwise. It is the same as Insert,
+// named for the lookup-or-add pattern of ordered maps, except that in a multiset (see NewMultiset)
+// an existing value is returned without adding an occurrence. GetOrInsert panics if the tree is frozen.
+func (rbt *RBTree[T]) GetOrInsert(val T) (*RBNode[T], bool) {
+	return rbt.GetOrInsertFunc(val, func() T { return val })
+}
+
+// GetOrInsertFunc searches for the key in a single descent and returns the existing node and false if it is found.
+// Otherwise GetOrInsertFunc adds the value returned by build and returns the new node and true.
+// It is meant for composite values, e.g. key-value pairs compared by their keys: key needs only the fields used
+// by the comparison function, and build makes the complete value, which is called only if the key is missing.
+// The value returned by build must compare equal to key, otherwise the tree becomes invalid.
+// If the tree is limited with WithMaxCount, a value may be evicted like in Insert.
+// In a multiset (see NewMultiset), an existing key is returned without adding an occurrence.
+// GetOrInsertFunc panics if the tree is frozen.
+func (rbt *RBTree[T]) GetOrInsertFunc(key T, build func() T) (*RBNode[T], bool) {
+	rbt.checkMutable()
+
+	if rbt.maxCount > 0 && rbt.Count >= rbt.maxCount {
+		if rbn, ok := rbt.Find(key); ok {
+			return rbn, false
+		}
+
+		return rbt.Insert(build())
+	}
+
+	if rbt.root == nil {
+		return rbt.insertRoot(build()), true
+	}
+
+	insertedNode, ok := rbt.root.insertFunc(key, build, rbt.cmp, rbt.pool)
+	if !ok {
+		return insertedNode, false
+	}
+
+	rbt.fixInsert(insertedNode)
+
+	return insertedNode, true
+}
+
+// InsertOrReplace adds a new value like Insert and returns the new node and true.
+// If an equal value exists in the tree, it is overwritten with the new value, and the node and false are returned.
+// Since the values compare equal, the order and the augmented data are kept without rebalancing, e.g. when the value is a key-value pair
+// compared by its key, the value of the key is updated. If the tree is limited with WithMaxCount and the new value
+// itself would be evicted (see InsertEvict), nil and false are returned. In a multiset (see NewMultiset), an existing value
+// is overwritten as well instead of adding an occurrence. InsertOrReplace panics if the tree is frozen.
+func (rbt *RBTree[T]) InsertOrReplace(val T) (*RBNode[T], bool) {
+	rbt.checkMutable()
+
+	if rbt.multiset {
+		if rbn, ok := rbt.Find(val); ok {
+			rbn.Val = val
+
+			return rbn, false
+		}
+	}
+
+	rbn, ok := rbt.Insert(val)
+	if !ok && rbn != nil {
+		rbn.Val = val
+	}
+
+	return rbn, ok
+}
+
+// InsertAll adds the values to the tree one by one like Insert and returns the number of newly added values.
+// Values, which already exist in the tree or repeat earlier values, are skipped, except in a multiset (see NewMultiset),
+// where every value is added as an occurrence and counted. InsertAll panics if the tree is frozen.
+func (rbt *RBTree[T]) InsertAll(vals ...T) int {
+	rbt.checkMutable()
+
+	inserted := 0
+
+	for _, val := range vals {
+		if _, ok := rbt.Insert(val); ok {
+			inserted++
+		}
+	}
+
+	return inserted
+}
+
+// MustInsert adds a new value to the red-black tree like Insert and returns the newly inserted node.
+// MustInsert panics if the value already exists in the tree, even in a multiset (see NewMultiset), or if the tree is frozen.
+// It is meant for tests and setup code, where a duplicate is a programming error.
+func (rbt *RBTree[T]) MustInsert(val T) *RBNode[T] {
+	if rbt.multiset {
+		if _, ok := rbt.Find(val); ok {
+			panic(fmt.Sprintf("rbtree: value %v already exists", val))
+		}
+	}
+
+	rbn, ok := rbt.Insert(val)
+	if !ok {
+		panic(fmt.Sprintf("rbtree: value %v already exists", val))
+	}
+
+	return rbn
+}
+
+// appendMax adds a value bigger than all values of the tree and fixes the tree afterwards if necessary.
+// The new node becomes the right child of the Max node, so no search is needed.
+func (rbt *RBTree[T]) appendMax(val T) {
+	if rbt.root == nil {
+		_, _ = rbt.Insert(val)
+
+		return
+	}
+
+	rbt.Max.right = newNode(val, rbt.Max, rbt.pool)
+	rbt.Max = rbt.Max.right
+
+	rbt.pushNewest(rbt.Max)
+	rbt.updatePath(rbt.Max)
+
+	if !rbt.Max.parent.isBlack {
+		rbt.solveDoubleRed(rbt.Max.parent)
+	}
+	rbt.Count++
+}
+
+func (rbt *RBTree[T]) String() string {
+	return rbt.StringFunc(sprint[T])
+}
+
+// StringIndent makes a multi-string depiction of the tree, similarly to String,
+// but every level of the tree is indented by width spaces. Negative width is treated as 0,
+// in which case the values are not indented at all.
+func (rbt *RBTree[T]) StringIndent(width int) string {
+	return rbt.StringIndentFunc(width, sprint[T])
+}
+
+// StringFunc makes a multi-string depiction of the tree, similarly to String,
+// but the values are formatted with format.
+func (rbt *RBTree[T]) StringFunc(format func(T) string) string {
+	return rbt.StringIndentFunc(1, format)
+}
+
+// StringIndentFunc makes a multi-string depiction of the tree, in which every level of the tree
+// is indented by width spaces and the values are formatted with format. Negative width is treated as 0,
+// in which case the values are not indented at all.
+func (rbt *RBTree[T]) StringIndentFunc(width int, format func(T) string) string {
+	if rbt.root == nil {
+		return ""
+	}
+
+	var result string
+
+	rbt.root.recString(&result, 0, max(width, 0), format)
+
+	return result
+}
+
+// PrettyPrint makes a top-down depiction of the tree with branch characters like the tree command.
+// Every node is written on its own line with its value formatted with %v and its color, (R) or (B),
+// followed by its left and right children. If a node has only one child, the other one is depicted as nil.
+// For an empty tree, an empty string is returned.
+func (rbt *RBTree[T]) PrettyPrint() string {
+	if rbt.root == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	rbt.root.recPrettyPrint(&sb, "", "")
+
+	return sb.String()
+}
+
+// sprint formats the value with %v.
+func sprint[T any](val T) string {
+	return fmt.Sprint(val)
+}
+
+// ToDOT makes a Graphviz depiction of the tree in the DOT language, which can be rendered with e.g. dot -Tpng.
+// Every node is labeled with its value formatted with %v and filled with its color.
+// Nil leaves are depicted as small black boxes. Nodes are numbered in preorder, left child before right,
+// so the output is the same for the same tree shape.
+func (rbt *RBTree[T]) ToDOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph RBTree {\n")
+	sb.WriteString("\tnode [style=filled, fontcolor=white];\n")
+
+	if rbt.root != nil {
+		var id int
+
+		rbt.root.recDOT(&sb, &id)
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// ToSlice returns the values of the tree in ascending order. For an empty tree, an empty non-nil slice is returned.
+func (rbt *RBTree[T]) ToSlice() []T {
+	vals := make([]T, 0, rbt.Count)
+
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		for range rbn.Multiplicity() {
+			vals = append(vals, rbn.Val)
+		}
+	}
+
+	return vals
+}
+
+// ColoredSlice returns the values of the tree together with their colors in ascending order.
+// It is a snapshot of the state of the tree, e.g. for golden-file tests. For an empty tree, an empty slice is returned.
+func (rbt *RBTree[T]) ColoredSlice() []struct {
+	Val   T
+	Black bool
+} {
+	colored := make([]struct {
+		Val   T
+		Black bool
+	}, 0, rbt.Count)
+
+	for rbn := range rbt.Nodes() {
+		colored = append(colored, struct {
+			Val   T
+			Black bool
+		}{rbn.Val, rbn.IsBlack()})
+	}
+
+	return colored
+}
+
+// Clear deletes all values from the tree, keeping its comparison function and options, so the tree can be reused.
+// Clear panics if the tree is frozen.
+func (rbt *RBTree[T]) Clear() {
+	rbt.checkMutable()
+
+	rbt.root, rbt.Min, rbt.Max = nil, nil, nil
+	rbt.oldest, rbt.newest = nil, nil
+	rbt.Count = 0
+}
+
+// IsEmpty checks if the tree has no values.
+func (rbt *RBTree[T]) IsEmpty() bool {
+	return rbt.root == nil
+}
+
+// Len returns the number of values in the tree. It is the read-only equivalent of Count.
+func (rbt *RBTree[T]) Len() int {
+	return rbt.Count
+}
+
+// Find returns the node pointer and true if a node with particular value was found in the red-black tree.
+func (rbt *RBTree[T]) Find(val T) (*RBNode[T], bool) {
+	if rbt.root == nil {
+		return nil, false
+	}
+
+	return rbt.root.find(val, rbt.cmp)
+}
+
+// Contains checks if the value exists in the tree. It returns false for an empty tree or a tree without a comparison function.
+func (rbt *RBTree[T]) Contains(val T) bool {
+	if rbt.cmp == nil {
+		return false
+	}
+
+	_, ok := rbt.Find(val)
+
+	return ok
+}
+
+// PathTo returns the nodes on the path from the root down to the node with particular value (inclusive)
+// and true if the value was found in the red-black tree. It returns an empty slice and false otherwise.
+func (rbt *RBTree[T]) PathTo(val T) ([]*RBNode[T], bool) {
+	var path []*RBNode[T]
+
+	for rbn := rbt.root; rbn != nil; {
+		path = append(path, rbn)
+
+		result := rbt.cmp(val, rbn.Val)
+
+		switch {
+		case result < 0:
+			rbn = rbn.left
+		case result > 0:
+			rbn = rbn.right
+		default:
+			return path, true
+		}
+	}
+
+	return []*RBNode[T]{}, false
+}
+
+// IsMin checks if the value is equal to the smallest value of the tree. IsMin returns false for an empty tree.
+func (rbt *RBTree[T]) IsMin(val T) bool {
+	return rbt.Min != nil && rbt.cmp(val, rbt.Min.Val) == 0
+}
+
+// IsMax checks if the value is equal to the biggest value of the tree. IsMax returns false for an empty tree.
+func (rbt *RBTree[T]) IsMax(val T) bool {
+	return rbt.Max != nil && rbt.cmp(val, rbt.Max.Val) == 0
+}
+
+// Oldest returns the node with the earliest inserted value and true if the tree is not empty
+// and tracks the insertion order (see WithInsertionOrder).
+func (rbt *RBTree[T]) Oldest() (*RBNode[T], bool) {
+	return rbt.oldest, rbt.oldest != nil
+}
+
+// Newest returns the node with the latest inserted value and true if the tree is not empty
+// and tracks the insertion order (see WithInsertionOrder).
+func (rbt *RBTree[T]) Newest() (*RBNode[T], bool) {
+	return rbt.newest, rbt.newest != nil
+}
+
+// ceilingNode returns the node with the smallest value greater than or equal to val or nil if there is no such node.
+func (rbt *RBTree[T]) ceilingNode(val T) *RBNode[T] {
+	if rbt.root == nil {
+		return nil
+	}
+
+	return rbt.root.ceiling(val, rbt.cmp)
+}
+
+// floorNode returns the node with the biggest value less than or equal to val or nil if there is no such node.
+func (rbt *RBTree[T]) floorNode(val T) *RBNode[T] {
+	if rbt.root == nil {
+		return nil
+	}
+
+	return rbt.root.floor(val, rbt.cmp)
+}
+
+// Bracket returns the nodes surrounding val in a single descent: the node with the biggest value less than val
+// and the node with the smallest value bigger than val, together with flags telling if they exist.
+// If val exists in the tree, both lower and upper are its node, so the bracket is inclusive at an exact match.
+// Missing bounds are returned as nil and false, e.g. when val is smaller than Min or bigger than Max.
+func (rbt *RBTree[T]) Bracket(val T) (lower, upper *RBNode[T], lowerOK, upperOK bool) {
+	for rbn := rbt.root; rbn != nil; {
+		switch result := rbt.cmp(val, rbn.Val); {
+		case result < 0:
+			upper = rbn
+			rbn = rbn.left
+		case result > 0:
+			lower = rbn
+			rbn = rbn.right
+		default:
+			return rbn, rbn, true, true
+		}
+	}
+
+	return lower, upper, lower != nil, upper != nil
+}
+
+// KNearest returns up to k values closest to center by dist, sorted by their distance to center.
+// Of two values at the same distance, the smaller one comes first. k is clamped to Count,
+// and an empty slice is returned if k <= 0. dist must not decrease as values move away from center
+// in either direction, e.g. the absolute difference of numbers. KNearest seeks to center
+// and expands outward in both directions, so it takes O(log n + k) time.
+// In a multiset (see NewMultiset), a value is returned as many times as it occurs.
+func (rbt *RBTree[T]) KNearest(center T, k int, dist func(a, b T) int) []T {
+	k = min(max(k, 0), rbt.Count)
+	nearest := make([]T, 0, k)
+
+	lower := rbt.floorNode(center)
+	upper := rbt.Min
+
+	if lower != nil {
+		upper = lower.nextNode()
+	}
+
+	for len(nearest) < k {
+		rbn := upper
+
+		if upper == nil || (lower != nil && dist(lower.Val, center) <= dist(upper.Val, center)) {
+			rbn = lower
+			lower, _ = lower.Prev()
+		} else {
+			upper = upper.nextNode()
+		}
+
+		for range min(rbn.Multiplicity(), k-len(nearest)) {
+			nearest = append(nearest, rbn.Val)
+		}
+	}
+
+	return nearest
+}
+
+// Successor returns the node with the smallest value bigger than val and true, or nil and false if there is no such node.
+// val does not have to be present in the tree.
+func (rbt *RBTree[T]) Successor(val T) (*RBNode[T], bool) {
+	var successor *RBNode[T]
+
+	for rbn := rbt.root; rbn != nil; {
+		if rbt.cmp(val, rbn.Val) < 0 {
+			successor = rbn
+			rbn = rbn.left
+		} else {
+			rbn = rbn.right
+		}
+	}
+
+	return successor, successor != nil
+}
+
+// Predecessor returns the node with the biggest value less than val and true, or nil and false if there is no such node.
+// val does not have to be present in the tree.
+func (rbt *RBTree[T]) Predecessor(val T) (*RBNode[T], bool) {
+	var predecessor *RBNode[T]
+
+	for rbn := rbt.root; rbn != nil; {
+		if rbt.cmp(val, rbn.Val) > 0 {
+			predecessor = rbn
+			rbn = rbn.right
+		} else {
+			rbn = rbn.left
+		}
+	}
+
+	return predecessor, predecessor != nil
+}
+
+// Between returns the number of values in the range [lo, hi] together with the first and the last nodes of the range.
+// Every bound is found in a single descent, which counts the values before it, so Between takes O(log n) time.
+// If the range is empty, Between returns 0 and nil nodes.
+func (rbt *RBTree[T]) Between(lo, hi T) (int, *RBNode[T], *RBNode[T]) {
+	first, below := rbt.ceilingRank(lo)
+	last, upTo := rbt.floorRank(hi)
+
+	if upTo <= below {
+		return 0, nil, nil
+	}
+
+	return upTo - below, first, last
+}
+
+// ceilingRank returns the node with the smallest value bigger than or equal to val, or nil if there is no such node,
+// together with the number of values less than val.
+func (rbt *RBTree[T]) ceilingRank(val T) (*RBNode[T], int) {
+	var ceiling *RBNode[T]
+
+	rank := 0
+
+	for rbn := rbt.root; rbn != nil; {
+		switch result := rbt.cmp(val, rbn.Val); {
+		case result < 0:
+			ceiling = rbn
+			rbn = rbn.left
+		case result > 0:
+			rank += rbn.left.Size() + rbn.Multiplicity()
+			rbn = rbn.right
+		default:
+			return rbn, rank + rbn.left.Size()
+		}
+	}
+
+	return ceiling, rank
+}
+
+// floorRank returns the node with the biggest value less than or equal to val, or nil if there is no such node,
+// together with the number of values less than or equal to val.
+func (rbt *RBTree[T]) floorRank(val T) (*RBNode[T], int) {
+	var floor *RBNode[T]
+
+	rank := 0
+
+	for rbn := rbt.root; rbn != nil; {
+		switch result := rbt.cmp(val, rbn.Val); {
+		case result < 0:
+			rbn = rbn.left
+		case result > 0:
+			floor = rbn
+			rank += rbn.left.Size() + rbn.Multiplicity()
+			rbn = rbn.right
+		default:
+			return rbn, rank + rbn.left.Size() + rbn.Multiplicity()
+		}
+	}
+
+	return floor, rank
+}
+
+// CountBetween returns the number of values v with lo < v < hi, excluding the bounds unlike Between.
+// CountBetween is computed with Rank, so it takes O(log n) time. If lo >= hi, 0 is returned.
+func (rbt *RBTree[T]) CountBetween(lo, hi T) int {
+	if rbt.cmp(lo, hi) >= 0 {
+		return 0
+	}
+
+	loRank, _ := rbt.Rank(lo)
+	hiRank, _ := rbt.Rank(hi)
+
+	if rbn, ok := rbt.Find(lo); ok {
+		loRank += rbn.Multiplicity()
+	}
+
+	return hiRank - loRank
+}
+
+// Select returns the node with the k-th smallest value (0-indexed) and true if k is in the range [0, Count).
+// Select descends using the sizes of the subtrees, so it takes O(log n) time.
+func (rbt *RBTree[T]) Select(k int) (*RBNode[T], bool) {
+	rbn := rbt.selectNode(k)
+
+	return rbn, rbn != nil
+}
+
+// At returns the value at the position i in ascending order (0-indexed) and true if i is in the range [0, Count).
+// At takes O(log n) time, so the tree can be accessed like a sorted slice.
+func (rbt *RBTree[T]) At(i int) (T, bool) {
+	rbn := rbt.selectNode(i)
+	if rbn == nil {
+		var val T
+
+		return val, false
+	}
+
+	return rbn.Val, true
+}
+
+// Rank returns the number of values less than val and true if val exists in the tree.
+// Rank descends using the sizes of the subtrees, so it takes O(log n) time.
+func (rbt *RBTree[T]) Rank(val T) (int, bool) {
+	rank := 0
+
+	for rbn := rbt.root; rbn != nil; {
+		switch result := rbt.cmp(val, rbn.Val); {
+		case result < 0:
+			rbn = rbn.left
+		case result > 0:
+			rank += rbn.left.Size() + rbn.Multiplicity()
+			rbn = rbn.right
+		default:
+			return rank + rbn.left.Size(), true
+		}
+	}
+
+	return rank, false
+}
+
+// selectNode returns the node with the k-th smallest value (0-indexed) or nil if k is out of range.
+func (rbt *RBTree[T]) selectNode(k int) *RBNode[T] {
+	if k < 0 || k >= rbt.Count {
+		return nil
+	}
+
+	for rbn := rbt.root; rbn != nil; {
+		leftSize := rbn.left.Size()
+
+		switch {
+		case k < leftSize:
+			rbn = rbn.left
+		case k >= leftSize+rbn.Multiplicity():
+			k -= leftSize + rbn.Multiplicity()
+			rbn = rbn.right
+		default:
+			return rbn
+		}
+	}
+
+	return nil
+}
+
+// MedianSplit returns the value splitting the tree into two most balanced parts and true if the tree is not empty.
+// There are Count/2 values smaller than the returned one, which makes it a natural pivot for recursive partitioning.
+func (rbt *RBTree[T]) MedianSplit() (T, bool) {
+	rbn := rbt.selectNode(rbt.Count / 2)
+	if rbn == nil {
+		var median T
+
+		return median, false
+	}
+
+	return rbn.Val, true
+}
+
+// Random returns a uniformly random value of the tree and true if the tree is not empty.
+// The value is selected by a random index drawn from r, which makes the result reproducible for a seeded source.
+// If r is nil, the global random source is used.
+func (rbt *RBTree[T]) Random(r *rand.Rand) (T, bool) {
+	if rbt.Count == 0 {
+		var val T
+
+		return val, false
+	}
+
+	index := 0
+
+	if r != nil {
+		index = r.IntN(rbt.Count)
+	} else {
+		index = rand.IntN(rbt.Count)
+	}
+
+	return rbt.selectNode(index).Val, true
 }
 
-// Clone copies the red-black tree to a new red-black tree with the same values and structure.
-// Clone returns a new red-black tree.
-func (rbt *RBTree[T]) Clone() *RBTree[T] {
+// Mode returns the most frequent value of the tree, the number of its occurrences and true if the tree is not empty.
+// Ties are resolved in favor of the smallest value. Unless the tree is a multiset, every value is stored once,
+// so Mode returns the smallest value with the number of occurrences equal to 1.
+func (rbt *RBTree[T]) Mode() (T, int, bool) {
+	var mode T
+
 	if rbt.root == nil {
-		return New[T](rbt.cmp)
+		return mode, 0, false
 	}
 
-	tree := &RBTree[T]{
-		root:  rbt.root.clone(),
-		cmp:   rbt.cmp,
-		Count: rbt.Count,
+	if !rbt.multiset {
+		return rbt.Min.Val, 1, true
 	}
 
-	tree.Min = tree.root.leftmost()
-	tree.Max = tree.root.rightmost()
+	modeNode := rbt.Min
 
-	return tree
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		if rbn.dups > modeNode.dups {
+			modeNode = rbn
+		}
+	}
+
+	return modeNode.Val, modeNode.Multiplicity(), true
 }
 
-// IsValid checks if the tree is a valid red-black tree.
-func (rbt *RBTree[T]) IsValid() bool {
-	if rbt.cmp == nil {
-		return false
-	}
+// Delete deletes a node with particular value from the red-black tree and fixes the tree if necessary.
+// Delete returns the deleted value and true if deletion was successful. It returns an empty value and false otherwise,
+// so the bool must be checked if the zero value is a legitimate element (or use DeletePtr).
+// In a multiset (see NewMultiset), Delete deletes one occurrence of the value. Delete panics if the tree is frozen.
+func (rbt *RBTree[T]) Delete(val T) (T, bool) {
+	rbt.checkMutable()
+
+	var del T
 
 	if rbt.root == nil {
-		return rbt.Min == nil && rbt.Max == nil && rbt.Count == 0
+		return del, false
 	}
 
-	if rbt.root.parent != nil || !rbt.root.isBlack {
-		return false
+	rbnDelete, ok := rbt.root.find(val, rbt.cmp)
+	if !ok {
+		return del, false
 	}
 
-	blackHeight, count := 0, 0
-	_, isValid := rbt.root.isValid(&blackHeight, 0, rbt.cmp)
+	return rbt.deleteNode(rbnDelete), true
+}
 
-	if !isValid || rbt.Min != rbt.root.leftmost() || rbt.Max != rbt.root.rightmost() {
-		return false
+// deleteNode deletes the node of the tree and fixes the tree if necessary. deleteNode returns the deleted value.
+func (rbt *RBTree[T]) deleteNode(rbnDelete *RBNode[T]) T {
+	val := rbnDelete.Val
+
+	if rbnDelete.dups > 0 {
+		rbnDelete.dups--
+		rbt.Count--
+		rbt.updatePath(rbnDelete)
+
+		return val
 	}
+	rbt.unlinkOrder(rbnDelete)
+	rbt.Count--
 
-	for i, ok := rbt.Min, true; ok; i, ok = i.Next() {
-		count++
+	if rbt.Count == 0 {
+		rbt.release(rbt.root)
+		rbt.root = nil
+		rbt.Min = nil
+		rbt.Max = nil
+
+		return val
+	}
+
+	if rbt.cmp(val, rbt.Min.Val) == 0 {
+		rbt.Min, _ = rbt.Min.Next()
+	}
+
+	if rbt.cmp(val, rbt.Max.Val) == 0 {
+		rbt.Max, _ = rbt.Max.Prev()
 	}
 
-	return count == rbt.Count
+	rbt.updatePath(rbt.deleteCheckChildren(rbnDelete))
+
+	return val
 }
 
-// EqualTo checks if both trees have the same structure and nodes.
-func (rbt *RBTree[T]) EqualTo(anotherRBT *RBTree[T]) bool {
-	if anotherRBT == nil {
+// DeleteNode deletes the node from the tree directly, without searching for its value, and fixes the tree if necessary.
+// The node must belong to the tree. DeleteNode returns false if the node is nil.
+// The nodes of the other values are kept, so they can still be used after the deletion.
+// DeleteNode panics if the tree is frozen.
+func (rbt *RBTree[T]) DeleteNode(rbn *RBNode[T]) bool {
+	rbt.checkMutable()
+
+	if rbn == nil {
 		return false
 	}
 
-	if rbt.root == nil && anotherRBT.root == nil {
-		return true
+	rbt.deleteNode(rbn)
+
+	return true
+}
+
+// DeletePtr deletes a node with particular value like Delete, but returns a pointer to a copy of the deleted value.
+// On a miss DeletePtr returns nil and false, so a miss can't be confused with a deleted zero value.
+// DeletePtr panics if the tree is frozen.
+func (rbt *RBTree[T]) DeletePtr(val T) (*T, bool) {
+	del, ok := rbt.Delete(val)
+	if !ok {
+		return nil, false
 	}
 
-	if rbt.root == nil || anotherRBT.root == nil {
-		return false
+	return &del, true
+}
+
+// PopMin deletes the smallest value from the tree and returns it together with true.
+// The Min node is deleted directly without searching. PopMin returns an empty value and false if the tree is empty.
+// PopMin panics if the tree is frozen.
+func (rbt *RBTree[T]) PopMin() (T, bool) {
+	rbt.checkMutable()
+
+	if rbt.Min == nil {
+		var val T
+
+		return val, false
 	}
 
-	if rbt.Count != anotherRBT.Count {
-		return false
+	return rbt.deleteNode(rbt.Min), true
+}
+
+// PopMax deletes the biggest value from the tree and returns it together with true.
+// The Max node is deleted directly without searching. PopMax returns an empty value and false if the tree is empty.
+// PopMax panics if the tree is frozen.
+func (rbt *RBTree[T]) PopMax() (T, bool) {
+	rbt.checkMutable()
+
+	if rbt.Max == nil {
+		var val T
+
+		return val, false
 	}
 
-	return rbt.root.equalTo(anotherRBT.root, rbt.cmp)
+	return rbt.deleteNode(rbt.Max), true
 }
 
-// Insert adds a new value to the red-black tree and fixes the tree afterwards if necessary.
-// If the insertion was successful, the newly inserted node and true are returned.
-// Otherwise the existent node and false are returned.
-func (rbt *RBTree[T]) Insert(val T) (*RBNode[T], bool) {
-	if rbt.root == nil {
-		rbt.root = &RBNode[T]{
-			Val:     val,
-			isBlack: true,
-		}
+// DeleteMin deletes the smallest value from the tree like PopMin, but does not return it.
+// DeleteMin returns false if the tree is empty. It panics if the tree is frozen.
+func (rbt *RBTree[T]) DeleteMin() bool {
+	_, ok := rbt.PopMin()
 
-		rbt.Min = rbt.root
-		rbt.Max = rbt.root
+	return ok
+}
 
-		rbt.Count++
+// DeleteMax deletes the biggest value from the tree like PopMax, but does not return it.
+// DeleteMax returns false if the tree is empty. It panics if the tree is frozen.
+func (rbt *RBTree[T]) DeleteMax() bool {
+	_, ok := rbt.PopMax()
 
-		return rbt.root, true
-	}
+	return ok
+}
 
-	insertedNode, ok := rbt.root.insert(val, rbt.cmp)
-	if !ok {
-		return insertedNode, false
+// PopMinN removes up to n smallest values from the tree and returns them in ascending order.
+// n is clamped to Count, and an empty slice is returned if n <= 0. If all values are popped, the tree is just cleared.
+// If deleting the values one by one from the Min end would take longer than rebuilding the tree, the remaining nodes
+// are relinked into a balanced tree in a single pass instead, so PopMinN takes O(min(n log Count, Count)) time.
+// PopMinN panics if the tree is frozen.
+func (rbt *RBTree[T]) PopMinN(n int) []T {
+	rbt.checkMutable()
+
+	n = min(max(n, 0), rbt.Count)
+	popped := make([]T, 0, n)
+
+	if n == rbt.Count {
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			for range rbn.Multiplicity() {
+				popped = append(popped, rbn.Val)
+			}
+		}
+
+		rbt.Clear()
+
+		return popped
 	}
 
-	if rbt.cmp(val, rbt.Min.Val) < 0 {
-		rbt.Min = insertedNode
-	} else if rbt.cmp(val, rbt.Max.Val) > 0 {
-		rbt.Max = insertedNode
+	if !rbt.relinkIsCheaper(n) {
+		for range n {
+			popped = append(popped, rbt.deleteNode(rbt.Min))
+		}
+
+		return popped
 	}
 
-	if !insertedNode.parent.isBlack {
-		rbt.solveDoubleRed(insertedNode.parent)
+	for rbn := rbt.Min; len(popped) < n; rbn = rbn.nextNode() {
+		for range min(rbn.Multiplicity(), n-len(popped)) {
+			popped = append(popped, rbn.Val)
+		}
 	}
 
-	rbt.Count++
+	rbt.keepRanks(n, rbt.Count)
 
-	return insertedNode, true
+	return popped
 }
 
-func (rbt *RBTree[T]) String() string {
-	if rbt.root == nil {
-		return ""
+// relinkIsCheaper checks if removing k values by relinking the remaining nodes, which takes O(Count) time,
+// is cheaper than k deletions, which take O(log Count) time each because of the updates of the augmented data.
+func (rbt *RBTree[T]) relinkIsCheaper(k int) bool {
+	return k*bits.Len(uint(rbt.Count)) > rbt.Count
+}
+
+// keepRanks keeps only the values with the ranks in [lo, hi), where 0 <= lo < hi <= Count, in a single pass.
+// The kept nodes are relinked into a balanced tree like in RebalanceSubtree, and a node with occurrences
+// on both sides of a bound keeps only the ones in the range. The other nodes are removed from the insertion order
+// and released.
+func (rbt *RBTree[T]) keepRanks(lo, hi int) {
+	var kept, removed []*RBNode[T]
+
+	rank := 0
+
+	for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+		start, end := max(rank, lo), min(rank+rbn.Multiplicity(), hi)
+		rank += rbn.Multiplicity()
+
+		if start >= end {
+			removed = append(removed, rbn)
+
+			continue
+		}
+
+		rbn.dups = end - start - 1
+		kept = append(kept, rbn)
 	}
 
-	var result string
+	for _, rbn := range removed {
+		rbt.unlinkOrder(rbn)
+	}
 
-	rbt.root.recString(&result, 0)
+	for _, rbn := range removed {
+		rbt.release(rbn)
+	}
 
-	return result
+	heights := make(map[int][2]uint64)
+	black, _ := balancedBlackHeightSets(len(kept), heights)
+
+	rbt.root = rbt.linkBalanced(kept, nil, bits.Len64(black)-1, heights)
+	rbt.Min, rbt.Max = kept[0], kept[len(kept)-1]
+	rbt.Count = hi - lo
 }
 
-// Find returns the node pointer and true if a node with particular value was found in the red-black tree.
-func (rbt *RBTree[T]) Find(val T) (*RBNode[T], bool) {
-	if rbt.root == nil {
-		return nil, false
+// DeleteRangeFunc deletes the values in the range [lo, hi], which satisfy pred, and returns the number of deleted values.
+// Only the range is walked, so DeleteRangeFunc takes O(log n + k + d log n) time for k values in the range
+// and d deleted values. The values are collected before any deletion, since deleting restructures the tree.
+// DeleteRangeFunc panics if the tree is frozen.
+func (rbt *RBTree[T]) DeleteRangeFunc(lo, hi T, pred func(T) bool) int {
+	rbt.checkMutable()
+
+	vals := slices.Collect(rbt.RangeFunc(lo, hi, pred))
+
+	for _, val := range vals {
+		_, _ = rbt.Delete(val)
 	}
 
-	return rbt.root.find(val, rbt.cmp)
+	return len(vals)
 }
 
-// Delete deletes a node with particular value from the red-black tree and fixes the tree if necessary.
-// Delete returns the deleted value and true if deletion was successful. It returns an empty value and false otherwise.
-func (rbt *RBTree[T]) Delete(val T) (T, bool) {
-	var del T
+// KeepFirst deletes all values except the n smallest ones. Nothing is deleted if n >= Count,
+// and the tree is cleared if n <= 0. The values are deleted from the Max end, so no search is needed,
+// or the kept nodes are relinked like in PopMinN if that is cheaper, so KeepFirst takes O(min((Count - n) log Count, Count)) time.
+// KeepFirst panics if the tree is frozen.
+func (rbt *RBTree[T]) KeepFirst(n int) {
+	rbt.checkMutable()
 
-	if rbt.root == nil {
-		return del, false
+	if n <= 0 {
+		rbt.Clear()
+
+		return
 	}
 
-	rbnDelete, ok := rbt.root.find(val, rbt.cmp)
-	if !ok {
-		return del, false
+	if n < rbt.Count && rbt.relinkIsCheaper(rbt.Count-n) {
+		rbt.keepRanks(0, n)
+
+		return
 	}
 
-	val = rbnDelete.Val
-	rbt.Count--
+	for rbt.Count > n {
+		rbt.deleteNode(rbt.Max)
+	}
+}
 
-	if rbt.Count == 0 {
-		rbt.root = nil
-		rbt.Min = nil
-		rbt.Max = nil
+// KeepLast deletes all values except the n biggest ones. Nothing is deleted if n >= Count,
+// and the tree is cleared if n <= 0. The values are deleted from the Min end, so no search is needed,
+// or the kept nodes are relinked like in PopMinN if that is cheaper, so KeepLast takes O(min((Count - n) log Count, Count)) time.
+// KeepLast panics if the tree is frozen.
+func (rbt *RBTree[T]) KeepLast(n int) {
+	rbt.checkMutable()
+
+	if n <= 0 {
+		rbt.Clear()
 
-		return val, true
+		return
 	}
 
-	if rbt.cmp(val, rbt.Min.Val) == 0 {
-		rbt.Min, _ = rbt.Min.Next()
+	if n < rbt.Count && rbt.relinkIsCheaper(rbt.Count-n) {
+		rbt.keepRanks(rbt.Count-n, rbt.Count)
+
+		return
 	}
 
-	if rbt.cmp(val, rbt.Max.Val) == 0 {
-		rbt.Max, _ = rbt.Max.Prev()
+	for rbt.Count > n {
+		rbt.deleteNode(rbt.Min)
+	}
+}
+
+// MustDelete deletes a node with particular value like Delete and returns the deleted value.
+// MustDelete panics if the value does not exist in the tree or if the tree is frozen.
+// It is meant for tests and setup code, where a missing value is a programming error.
+func (rbt *RBTree[T]) MustDelete(val T) T {
+	del, ok := rbt.Delete(val)
+	if !ok {
+		panic(fmt.Sprintf("rbtree: value %v does not exist", val))
+	}
+
+	return del
+}
+
+// SimulateDelete measures the rebalancing work that deleting the value would cause without mutating the tree.
+// SimulateDelete returns the number of rotations, the number of nodes changing their colors
+// and true if the value was found. The deletion is performed on a clone of the tree, which takes O(n) time.
+func (rbt *RBTree[T]) SimulateDelete(val T) (int, int, bool) {
+	if _, ok := rbt.Find(val); !ok {
+		return 0, 0, false
 	}
 
-	rbt.deleteCheckChildren(rbnDelete)
+	tree := rbt.Clone()
+	tree.counters = &rebalanceCounters{}
+
+	_, _ = tree.Delete(val)
 
-	return val, true
+	return tree.counters.rotations, tree.counters.colorFlips, true
 }
 
 // deleteCheckChildren is the continuation of the Delete function (split for readability).
-func (rbt *RBTree[T]) deleteCheckChildren(rbnDelete *RBNode[T]) {
+// A node with two children is replaced by the node of the next value, and a node with one child is replaced by the child,
+// so the nodes of the other values keep their values. deleteCheckChildren returns the lowest node with a changed subtree.
+func (rbt *RBTree[T]) deleteCheckChildren(rbnDelete *RBNode[T]) *RBNode[T] {
+	var changed *RBNode[T]
+
 	switch {
 	case rbnDelete.left == nil && rbnDelete.right == nil: // no children
 		rbt.deleteNoChildren(rbnDelete)
+		changed = rbnDelete.parent
 	case rbnDelete.left == nil: // one child
-		rbnDelete.Val = rbnDelete.right.Val
-		rbnDelete.right = nil
+		changed = rbnDelete.right
+		rbt.replaceNode(rbnDelete, rbnDelete.right)
 	case rbnDelete.right == nil:
-		rbnDelete.Val = rbnDelete.left.Val
-		rbnDelete.left = nil
-	default: // left and right: find the next closest value, swap values, delete leaf
-		rbnDelete.Val = rbt.findAndDeleteLeftmost(rbnDelete.right) // find and delete the leftmost successor of the right child
+		changed = rbnDelete.left
+		rbt.replaceNode(rbnDelete, rbnDelete.left)
+	default: // left and right: find the next closest value, delete its node and put it in place of the deleted node
+		leftmost := rbt.findAndDeleteLeftmost(rbnDelete.right) // find and delete the leftmost successor of the right child
+		changed = leftmost.parent
+
+		if changed == rbnDelete {
+			changed = leftmost
+		}
+
+		rbt.replaceNode(rbnDelete, leftmost)
+	}
+
+	rbt.release(rbnDelete)
+
+	return changed
+}
+
+// release resets the node removed from the tree and puts it into the pool, if the tree has one.
+func (rbt *RBTree[T]) release(rbn *RBNode[T]) {
+	if rbt.pool == nil {
+		return
+	}
+
+	*rbn = RBNode[T]{}
+	rbt.pool.Put(rbn)
+}
+
+// RotateLeft moves the node down to the left, so that its right child takes its place.
+// RotateLeft does not recolor nodes, so the tree may become invalid afterwards. It is meant for learning
+// and experimenting with balancing: call IsValid to observe the effect of a rotation.
+// The node must belong to the tree. RotateLeft returns an error if the tree is frozen or the node or its right child is nil.
+func (rbt *RBTree[T]) RotateLeft(rbn *RBNode[T]) error {
+	if rbt.frozen {
+		return ErrFrozen
+	}
+
+	if rbn == nil {
+		return ErrNilNode
+	}
+
+	if rbn.right == nil {
+		return ErrNoRightChild
+	}
+
+	rbt.rotateLeft(rbn)
+
+	return nil
+}
+
+// RotateRight moves the node down to the right, so that its left child takes its place.
+// RotateRight does not recolor nodes, so the tree may become invalid afterwards. It is meant for learning
+// and experimenting with balancing: call IsValid to observe the effect of a rotation.
+// The node must belong to the tree. RotateRight returns an error if the tree is frozen or the node or its left child is nil.
+func (rbt *RBTree[T]) RotateRight(rbn *RBNode[T]) error {
+	if rbt.frozen {
+		return ErrFrozen
 	}
 
-	if rbt.cmp(rbnDelete.Val, rbt.Min.Val) == 0 {
-		rbt.Min = rbnDelete
+	if rbn == nil {
+		return ErrNilNode
 	}
 
-	if rbt.cmp(rbnDelete.Val, rbt.Max.Val) == 0 {
-		rbt.Max = rbnDelete
+	if rbn.left == nil {
+		return ErrNoLeftChild
 	}
+
+	rbt.rotateRight(rbn)
+
+	return nil
 }
 
 // rotateRight moves the node down to the right.
@@ -236,6 +1742,10 @@ func (rbt *RBTree[T]) deleteCheckChildren(rbnDelete *RBNode[T]) {
 //	 / \               / \
 //	c   d             d   e
 func (rbt *RBTree[T]) rotateRight(rbn *RBNode[T]) {
+	if rbt.counters != nil {
+		rbt.counters.rotations++
+	}
+
 	if rbt.root == rbn {
 		rbt.root = rbn.left
 	}
@@ -257,6 +1767,9 @@ func (rbt *RBTree[T]) rotateRight(rbn *RBNode[T]) {
 			rbn.parent.parent.right = rbn.parent
 		}
 	}
+
+	rbt.update(rbn)
+	rbt.update(rbn.parent)
 }
 
 // rotateLeft moves the node down to the left.
@@ -267,6 +1780,10 @@ func (rbt *RBTree[T]) rotateRight(rbn *RBNode[T]) {
 //	   / \       / \
 //	  c   d     e   c
 func (rbt *RBTree[T]) rotateLeft(rbn *RBNode[T]) {
+	if rbt.counters != nil {
+		rbt.counters.rotations++
+	}
+
 	if rbt.root == rbn {
 		rbt.root = rbn.right
 	}
@@ -288,6 +1805,9 @@ func (rbt *RBTree[T]) rotateLeft(rbn *RBNode[T]) {
 			rbn.parent.parent.right = rbn.parent
 		}
 	}
+
+	rbt.update(rbn)
+	rbt.update(rbn.parent)
 }
 
 // solveDoubleRed maintains the validity of the red-black tree if a red node has a red child.
@@ -299,8 +1819,8 @@ func (rbt *RBTree[T]) solveDoubleRed(rbn *RBNode[T]) {
 			rbn = rbn.parent
 		}
 
-		rbn.parent.isBlack = false
-		rbn.isBlack = true
+		rbt.paint(rbn.parent, false)
+		rbt.paint(rbn, true)
 
 		rbt.rotateLeft(rbn.parent)
 	case isBlack(rbn.parent.right): // if sibling is right and black
@@ -309,16 +1829,16 @@ func (rbt *RBTree[T]) solveDoubleRed(rbn *RBNode[T]) {
 			rbn = rbn.parent
 		}
 
-		rbn.parent.isBlack = false
-		rbn.isBlack = true
+		rbt.paint(rbn.parent, false)
+		rbt.paint(rbn, true)
 
 		rbt.rotateRight(rbn.parent)
 	default: // if sibling is red
-		rbn.parent.left.isBlack = true
-		rbn.parent.right.isBlack = true
+		rbt.paint(rbn.parent.left, true)
+		rbt.paint(rbn.parent.right, true)
 
 		if rbn.parent.parent != nil {
-			rbn.parent.isBlack = false
+			rbt.paint(rbn.parent, false)
 			if !rbn.parent.parent.isBlack {
 				rbt.solveDoubleRed(rbn.parent.parent)
 			}
@@ -347,8 +1867,8 @@ func (rbt *RBTree[T]) solveDoubleBlack(rbn *RBNode[T]) {
 	}
 
 	if sibling != nil && !sibling.isBlack { // red sibling
-		parent.isBlack = false
-		sibling.isBlack = true
+		rbt.paint(parent, false)
+		rbt.paint(sibling, true)
 
 		if siblingIsRight {
 			rbt.rotateLeft(parent)
@@ -361,7 +1881,7 @@ func (rbt *RBTree[T]) solveDoubleBlack(rbn *RBNode[T]) {
 
 	// black sibling with black children
 	if sibling.isBlack && isBlack(sibling.left) && isBlack(sibling.right) {
-		sibling.isBlack = false
+		rbt.paint(sibling, false)
 
 		if parent.isBlack {
 			rbt.solveDoubleBlack(parent)
@@ -369,7 +1889,7 @@ func (rbt *RBTree[T]) solveDoubleBlack(rbn *RBNode[T]) {
 			return
 		}
 
-		parent.isBlack = true
+		rbt.paint(parent, true)
 
 		return
 	}
@@ -384,14 +1904,14 @@ func (rbt *RBTree[T]) doubleBlackBlackSiblingRedChild(parent *RBNode[T], sibling
 	leftIsBlack := isBlack(sibling.left)
 
 	if rightIsBlack == siblingIsRight && leftIsBlack != siblingIsRight { // 🦊
-		sibling.isBlack = false
+		rbt.paint(sibling, false)
 
 		if siblingIsRight {
-			sibling.left.isBlack = true
+			rbt.paint(sibling.left, true)
 			rbt.rotateRight(sibling)
 			sibling = parent.right
 		} else {
-			sibling.right.isBlack = true
+			rbt.paint(sibling.right, true)
 			rbt.rotateLeft(sibling)
 			sibling = parent.left
 		}
@@ -400,29 +1920,29 @@ func (rbt *RBTree[T]) doubleBlackBlackSiblingRedChild(parent *RBNode[T], sibling
 		leftIsBlack = isBlack(sibling.left)
 	}
 
-	sibling.isBlack = parent.isBlack
-	parent.isBlack = true
+	rbt.paint(sibling, parent.isBlack)
+	rbt.paint(parent, true)
 
 	if siblingIsRight && !rightIsBlack {
-		sibling.right.isBlack = true
+		rbt.paint(sibling.right, true)
 
 		rbt.rotateLeft(parent)
 	} else if !siblingIsRight && !leftIsBlack {
-		sibling.left.isBlack = true
+		rbt.paint(sibling.left, true)
 
 		rbt.rotateRight(parent)
 	}
 }
 
-// findAndDeleteLeftmost deletes the leftmost node and returns its value.
-func (rbt *RBTree[T]) findAndDeleteLeftmost(rbn *RBNode[T]) T {
+// findAndDeleteLeftmost deletes the leftmost node and returns it.
+func (rbt *RBTree[T]) findAndDeleteLeftmost(rbn *RBNode[T]) *RBNode[T] {
 	if rbn.left != nil {
 		return rbt.findAndDeleteLeftmost(rbn.left)
 	}
 
 	if rbn.right != nil {
 		rbn.right.parent = rbn.parent
-		rbn.right.isBlack = true
+		rbt.paint(rbn.right, true)
 
 		if rbn.parent.left == rbn {
 			rbn.parent.left = rbn.right
@@ -430,12 +1950,12 @@ func (rbt *RBTree[T]) findAndDeleteLeftmost(rbn *RBNode[T]) T {
 			rbn.parent.right = rbn.right
 		}
 
-		return rbn.Val
+		return rbn
 	}
 
 	rbt.deleteNoChildren(rbn)
 
-	return rbn.Val
+	return rbn
 }
 
 // deleteNoChildren deletes a node without children.
@@ -450,3 +1970,138 @@ func (rbt *RBTree[T]) deleteNoChildren(rbn *RBNode[T]) {
 		rbt.solveDoubleBlack(rbn)
 	}
 }
+
+// replaceNode puts the src node, which is detached from the tree or is the only child of dst, in place of the dst node.
+// src takes the parent, the children and the color of dst. The augmented data must be updated afterwards.
+func (rbt *RBTree[T]) replaceNode(dst, src *RBNode[T]) {
+	if dst.left != src {
+		src.left = dst.left
+	}
+
+	if dst.right != src {
+		src.right = dst.right
+	}
+
+	src.parent, src.isBlack = dst.parent, dst.isBlack
+
+	if src.left != nil {
+		src.left.parent = src
+	}
+
+	if src.right != nil {
+		src.right.parent = src
+	}
+
+	switch {
+	case dst.parent == nil:
+		rbt.root = src
+	case dst.parent.left == dst:
+		dst.parent.left = src
+	default:
+		dst.parent.right = src
+	}
+}
+
+// pushNewest appends the node to the end of the insertion order list.
+func (rbt *RBTree[T]) pushNewest(rbn *RBNode[T]) {
+	if !rbt.insertionOrder {
+		return
+	}
+
+	rbn.older = rbt.newest
+
+	if rbt.newest != nil {
+		rbt.newest.newer = rbn
+	} else {
+		rbt.oldest = rbn
+	}
+
+	rbt.newest = rbn
+}
+
+// unlinkOrder removes the node from the insertion order list.
+func (rbt *RBTree[T]) unlinkOrder(rbn *RBNode[T]) {
+	if !rbt.insertionOrder {
+		return
+	}
+
+	if rbn.older != nil {
+		rbn.older.newer = rbn.newer
+	} else {
+		rbt.oldest = rbn.newer
+	}
+
+	if rbn.newer != nil {
+		rbn.newer.older = rbn.older
+	} else {
+		rbt.newest = rbn.older
+	}
+
+	rbn.older, rbn.newer = nil, nil
+}
+
+// update recomputes the augmented data of the node from its children: the size of the subtree
+// and, if the tree is an interval tree, the node with the biggest high endpoint.
+func (rbt *RBTree[T]) update(rbn *RBNode[T]) {
+	if rbt.deferUpdates {
+		return
+	}
+
+	rbn.size = rbn.Multiplicity() + rbn.left.Size() + rbn.right.Size()
+
+	if rbt.cmpEnd == nil {
+		return
+	}
+
+	rbn.maxEnd = rbn
+
+	if rbn.left != nil && rbt.cmpEnd(rbn.left.maxEnd.Val, rbn.maxEnd.Val) > 0 {
+		rbn.maxEnd = rbn.left.maxEnd
+	}
+
+	if rbn.right != nil && rbt.cmpEnd(rbn.right.maxEnd.Val, rbn.maxEnd.Val) > 0 {
+		rbn.maxEnd = rbn.right.maxEnd
+	}
+}
+
+// updatePath recomputes the augmented data of the node and all its ancestors.
+func (rbt *RBTree[T]) updatePath(rbn *RBNode[T]) {
+	if rbt.deferUpdates {
+		return
+	}
+
+	for ; rbn != nil; rbn = rbn.parent {
+		rbt.update(rbn)
+	}
+}
+
+// updateSubtree recomputes the augmented data of every node of the subtree.
+// The nodes are visited in postorder by following the parent pointers, so no stack is needed.
+func (rbt *RBTree[T]) updateSubtree(rbn *RBNode[T]) {
+	if rbn == nil || rbt.deferUpdates {
+		return
+	}
+
+	for node := rbn.firstPostorder(); ; {
+		rbt.update(node)
+
+		if node == rbn {
+			return
+		}
+
+		if parent := node.parent; node == parent.left && parent.right != nil {
+			node = parent.right.firstPostorder()
+		} else {
+			node = parent
+		}
+	}
+}
+
+// paint sets the color of the node and counts the color flip if the rebalancing work is measured.
+func (rbt *RBTree[T]) paint(rbn *RBNode[T], black bool) {
+	if rbt.counters != nil && rbn.isBlack != black {
+		rbt.counters.colorFlips++
+	}
+
+	rbn.isBlack = black
+}
@@ -2,7 +2,13 @@ package rbtree
 
 import (
 	"cmp"
+	"errors"
+	"fmt"
 	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 )
 
@@ -18,6 +24,7 @@ func initRBTBefore() *RBTree[int] {
 		root: &RBNode[int]{
 			Val:     70,
 			isBlack: true,
+			size:    7,
 		},
 		cmp:   cmp.Compare[int],
 		Count: 7,
@@ -26,36 +33,42 @@ func initRBTBefore() *RBTree[int] {
 	rbtBefore.root.left = &RBNode[int]{
 		Val:     50,
 		isBlack: false,
+		size:    3,
 		parent:  rbtBefore.root,
 	}
 
 	rbtBefore.root.left.left = &RBNode[int]{
 		Val:     20,
 		isBlack: true,
+		size:    1,
 		parent:  rbtBefore.root.left,
 	}
 
 	rbtBefore.root.left.right = &RBNode[int]{
 		Val:     60,
 		isBlack: true,
+		size:    1,
 		parent:  rbtBefore.root.left,
 	}
 
 	rbtBefore.root.right = &RBNode[int]{
 		Val:     80,
 		isBlack: false,
+		size:    3,
 		parent:  rbtBefore.root,
 	}
 
 	rbtBefore.root.right.left = &RBNode[int]{
 		Val:     75,
 		isBlack: true,
+		size:    1,
 		parent:  rbtBefore.root.right,
 	}
 
 	rbtBefore.root.right.right = &RBNode[int]{
 		Val:     100,
 		isBlack: true,
+		size:    1,
 		parent:  rbtBefore.root.right,
 	}
 
@@ -65,6 +78,57 @@ func initRBTBefore() *RBTree[int] {
 	return rbtBefore
 }
 
+func TestTransaction(t *testing.T) {
+	t.Parallel()
+
+	errAbort := errors.New("abort")
+
+	t.Run("Transaction: commit", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		err := rbt.Transaction(func(rbt *RBTree[int]) error {
+			_, _ = rbt.Insert(10)
+			_, _ = rbt.Delete(70)
+
+			return nil
+		})
+
+		if err != nil || rbt.Count != 7 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Find(70); ok {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Find(10); !ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("Transaction: rollback", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		err := rbt.Transaction(func(rbt *RBTree[int]) error {
+			for i := range 100 {
+				_, _ = rbt.Insert(i)
+			}
+
+			_, _ = rbt.Delete(70)
+
+			return errAbort
+		})
+
+		if !errors.Is(err, errAbort) || !rbt.IsValid() || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+}
+
 func TestIsValid(t *testing.T) {
 	t.Parallel()
 
@@ -176,314 +240,928 @@ func TestIsValid(t *testing.T) {
 	})
 }
 
-func TestClone(t *testing.T) {
+func TestValidateVerbose(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Clone: empty tree", func(t *testing.T) {
+	t.Run("ValidateVerbose: no cmp", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			cmp: cmp.Compare[int],
+		if len((&RBTree[int]{}).ValidateVerbose()) != 1 {
+			t.Fail()
 		}
+	})
 
-		rbtCloned := rbt.Clone()
+	t.Run("ValidateVerbose: valid trees", func(t *testing.T) {
+		t.Parallel()
 
-		if rbtCloned.root != nil || rbtCloned.Count != 0 || rbtCloned.cmp == nil || rbtCloned.Max != nil || rbtCloned.Min != nil {
+		issues := NewOrdered[int]().ValidateVerbose()
+		if issues == nil || len(issues) != 0 || len(initRBTBefore().ValidateVerbose()) != 0 {
 			t.Fail()
 		}
 	})
 
-	t.Run("Clone: 3-node tree", func(t *testing.T) {
+	t.Run("ValidateVerbose: multiple issues", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			root: &RBNode[int]{
-				Val:     70,
-				isBlack: true,
-			},
-			cmp:   cmp.Compare[int],
-			Count: 3,
-		}
+		rbt := initRBTBefore()
 
-		rbt.root.left = &RBNode[int]{
-			Val:     50,
-			isBlack: true,
-			parent:  rbt.root,
-		}
+		rbt.root.isBlack = false
+		rbt.root.left.left.isBlack = false
+		rbt.root.right.right.Val = 10
+		rbt.Count++
 
-		rbt.root.right = &RBNode[int]{
-			Val:     80,
-			isBlack: true,
-			parent:  rbt.root,
+		expected := []string{
+			"root 70: root is red",
+			"node 50: red node has red parent 70",
+			"node 20: red node has red parent 50",
+			"node 50: black heights of the subtrees differ: 0 on the left, 1 on the right",
+			"node 80: red node has red parent 70",
+			"node 80: right child 10 is not bigger",
+			"tree: Count is 8, but the tree has 7 nodes",
 		}
 
-		rbt.Max = rbt.root.right
-		rbt.Min = rbt.root.left
-
-		rbtCloned := rbt.Clone()
-
-		if rbtCloned.root == nil || rbtCloned.Count != rbt.Count {
+		if !slices.Equal(rbt.ValidateVerbose(), expected) {
 			t.Fail()
 		}
+	})
 
-		if rbtCloned.cmp(rbtCloned.Min.Val, rbt.Min.Val) != 0 || rbtCloned.cmp(rbtCloned.Max.Val, rbt.Max.Val) != 0 {
-			t.Fail()
-		}
+	t.Run("ValidateVerbose: wrong parent", func(t *testing.T) {
+		t.Parallel()
 
-		if rbtCloned.root.left == nil || rbtCloned.root.left.parent != rbtCloned.root {
-			t.Fail()
-		}
+		rbt := initRBTBefore()
 
-		if rbtCloned.root.right == nil || rbtCloned.root.right.parent != rbtCloned.root {
-			t.Fail()
-		}
+		rbt.root.left.parent = rbt.root.right
 
-		if rbtCloned.cmp(rbtCloned.root.Val, rbt.root.Val) != 0 {
-			t.Fail()
+		expected := []string{
+			"node 70: left child 50 has a wrong parent",
+			"node 70: black heights of the subtrees differ: 0 on the left, 1 on the right",
+			"tree: Count is 7, but the tree has 4 nodes",
 		}
 
-		if rbtCloned.cmp(rbtCloned.root.left.Val, rbt.root.left.Val) != 0 {
+		if !slices.Equal(rbt.ValidateVerbose(), expected) {
 			t.Fail()
 		}
+	})
 
-		if rbtCloned.cmp(rbtCloned.root.right.Val, rbt.root.right.Val) != 0 {
-			t.Fail()
-		}
+	t.Run("ValidateVerbose: limited number of issues", func(t *testing.T) {
+		t.Parallel()
 
-		if rbtCloned.root.isBlack != rbt.root.isBlack {
-			t.Fail()
+		rbt := NewOrdered[int]()
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
 		}
 
-		if rbtCloned.root.left.isBlack != rbt.root.left.isBlack {
-			t.Fail()
+		for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+			rbn.isBlack = false
 		}
 
-		if rbtCloned.root.right.isBlack != rbt.root.right.isBlack {
+		if len(rbt.ValidateVerbose()) != maxValidationIssues {
 			t.Fail()
 		}
 	})
 }
 
-func TestEqualTo(t *testing.T) {
+func TestIsTree(t *testing.T) {
 	t.Parallel()
 
-	t.Run("EqualTo: empty trees", func(t *testing.T) {
+	t.Run("IsTree: empty tree", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			cmp: cmp.Compare[int],
-		}
-		anotherRBT := &RBTree[int]{
-			cmp: cmp.Compare[int],
-		}
-
-		if !rbt.EqualTo(anotherRBT) {
+		if !NewOrdered[int]().IsTree() {
 			t.Fail()
 		}
 	})
 
-	t.Run("EqualTo: empty and non-empty tree", func(t *testing.T) {
+	t.Run("IsTree: valid tree", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			cmp: cmp.Compare[int],
-		}
-		anotherRBT := initRBTBefore()
-
-		if rbt.EqualTo(anotherRBT) {
+		if !initRBTBefore().IsTree() {
 			t.Fail()
 		}
 	})
 
-	t.Run("EqualTo: nil and non-nil tree", func(t *testing.T) {
+	t.Run("IsTree: shared subtree", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			cmp: cmp.Compare[int],
-		}
+		rbt := initRBTBefore()
 
-		var anotherRBT *RBTree[int]
+		rbt.root.right.left = rbt.root.left
 
-		if rbt.EqualTo(anotherRBT) {
+		if rbt.IsTree() || rbt.IsValid() {
 			t.Fail()
 		}
 	})
 
-	t.Run("EqualTo: non-equal structure", func(t *testing.T) {
+	t.Run("IsTree: cycle", func(t *testing.T) {
 		t.Parallel()
 
 		rbt := initRBTBefore()
-		anotherRBT := initRBTBefore()
-
-		rbt.root.left.left.right = &RBNode[int]{
-			Val:     10,
-			isBlack: false,
-			parent:  rbt.root.left.left,
-		}
-
-		anotherRBT.root.right.right.right = &RBNode[int]{
-			Val:     110,
-			isBlack: false,
-			parent:  rbt.root.right.right,
-		}
 
-		rbt.Count++
-		anotherRBT.Count++
+		rbt.root.left.left.left = rbt.root
 
-		if rbt.EqualTo(anotherRBT) {
+		if rbt.IsTree() || rbt.IsValid() {
 			t.Fail()
 		}
 	})
+}
 
-	t.Run("EqualTo: non-equal structure - left node", func(t *testing.T) {
-		t.Parallel()
+func TestNewReverse(t *testing.T) {
+	t.Parallel()
 
-		rbt := initRBTBefore()
-		anotherRBT := initRBTBefore()
+	rbt := NewReverse[int]()
 
-		anotherRBT.root.left.left.left = &RBNode[int]{
-			Val:     10,
-			isBlack: false,
-			parent:  anotherRBT.root.left.left,
-		}
+	for _, val := range []int{50, 20, 70, 60, 80} {
+		_, _ = rbt.Insert(val)
+	}
 
-		rbt.root.right.right.right = &RBNode[int]{
-			Val:     110,
-			isBlack: false,
-			parent:  rbt.root.right.right,
-		}
+	if !rbt.IsValid() || rbt.Min.Val != 80 || rbt.Max.Val != 20 {
+		t.Fail()
+	}
 
-		rbt.Count++
-		anotherRBT.Count++
+	if !slices.Equal(slices.Collect(rbt.All()), []int{80, 70, 60, 50, 20}) || !slices.Equal(slices.Collect(rbt.Backward()), []int{20, 50, 60, 70, 80}) {
+		t.Fail()
+	}
 
-		if rbt.EqualTo(anotherRBT) {
-			t.Fail()
-		}
-	})
+	if _, ok := rbt.Delete(70); !ok || !rbt.IsValid() || rbt.Contains(70) || !rbt.Contains(60) {
+		t.Fail()
+	}
+}
 
-	t.Run("EqualTo: non-equal structure - right node", func(t *testing.T) {
-		t.Parallel()
+func TestNewPooled(t *testing.T) {
+	t.Parallel()
 
-		rbt := initRBTBefore()
-		anotherRBT := initRBTBefore()
+	t.Run("NewPooled: random inserts and deletes", func(t *testing.T) {
+		t.Parallel()
 
-		anotherRBT.root.left.left.right = &RBNode[int]{
-			Val:     10,
-			isBlack: false,
-			parent:  anotherRBT.root.left.left,
-		}
+		rbt := NewPooled(cmp.Compare[int], WithInsertionOrder[int]())
+		values := make(map[int]struct{})
 
-		rbt.root.right.right.right = &RBNode[int]{
-			Val:     110,
-			isBlack: false,
-			parent:  rbt.root.right.right,
-		}
+		for range 10000 {
+			val := rand.IntN(500)
 
-		rbt.Count++
-		anotherRBT.Count++
+			if rand.IntN(2) == 0 {
+				if _, ok := rbt.Insert(val); ok {
+					values[val] = struct{}{}
+				}
+			} else if _, ok := rbt.Delete(val); ok {
+				delete(values, val)
+			}
 
-		if rbt.EqualTo(anotherRBT) {
-			t.Fail()
+			if rbt.Count != len(values) || !rbt.IsValid() {
+				t.FailNow()
+			}
 		}
 	})
 
-	t.Run("EqualTo: different size", func(t *testing.T) {
+	t.Run("NewPooled: nodes of other values keep them", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
-		anotherRBT := initRBTBefore()
+		rbt := NewPooled(cmp.Compare[int])
+		rbt.InsertAll(4, 2, 6, 1, 3, 5, 7)
 
-		rbt.root.left.left.right = &RBNode[int]{
-			Val:     10,
-			isBlack: false,
-			parent:  rbt.root.left.left,
-		}
+		kept, _ := rbt.Find(5)
+		_, _ = rbt.Delete(4)
 
-		rbt.Count++
+		for val := 100; val < 110; val++ {
+			_, _ = rbt.Insert(val)
+		}
 
-		if rbt.EqualTo(anotherRBT) {
+		if rbn, ok := rbt.Find(5); !ok || rbn != kept || kept.Val != 5 || !rbt.IsValid() {
 			t.Fail()
 		}
 	})
 
-	t.Run("EqualTo: different values", func(t *testing.T) {
+	t.Run("NewPooled: random deletes keep nodes", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
-		anotherRBT := initRBTBefore()
+		rbt := NewPooled(cmp.Compare[int], WithInsertionOrder[int]())
+		nodes := make(map[int]*RBNode[int])
 
-		anotherRBT.root.Val = 75
+		for range 10000 {
+			val := rand.IntN(500)
 
-		if rbt.EqualTo(anotherRBT) {
-			t.Fail()
+			if rand.IntN(2) == 0 {
+				if rbn, ok := rbt.Insert(val); ok {
+					nodes[val] = rbn
+				}
+			} else if _, ok := rbt.Delete(val); ok {
+				delete(nodes, val)
+			}
+		}
+
+		for val, rbn := range nodes {
+			if found, ok := rbt.Find(val); !ok || found != rbn || rbn.Val != val {
+				t.FailNow()
+			}
 		}
 	})
 
-	t.Run("EqualTo: equal trees", func(t *testing.T) {
+	t.Run("NewPooled: reset nodes", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
-		anotherRBT := initRBTBefore()
+		rbt := NewPooled(cmp.Compare[int])
 
-		if !rbt.EqualTo(anotherRBT) {
-			t.Fail()
+		for i := range 10 {
+			_, _ = rbt.Insert(i)
 		}
-	})
-}
-
-func TestString(t *testing.T) {
-	t.Parallel()
 
-	t.Run("String: empty tree", func(t *testing.T) {
-		t.Parallel()
+		rbn := rbt.root
+		rbt.release(rbn)
 
-		rbt := &RBTree[int]{
-			cmp: cmp.Compare[int],
+		if *rbn != (RBNode[int]{}) {
+			t.Fail()
 		}
 
-		if rbt.String() != "" {
+		if reused := newNode(5, nil, rbt.pool); reused.isBlack || reused.left != nil || reused.size != 0 || reused.Val != 5 {
 			t.Fail()
 		}
 	})
 
-	t.Run("String: non-empty tree", func(t *testing.T) {
+	t.Run("NewPooled: copies share the pool", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
-		expectedResult := "   100\n  80\n   75\n 70\n   60\n  50\n   20\n"
+		rbt := NewPooled(cmp.Compare[int])
+		_, _ = rbt.Insert(1)
 
-		if rbt.String() != expectedResult {
+		if rbt.Clone().pool != rbt.pool || New(cmp.Compare[int]).pool != nil {
 			t.Fail()
 		}
 	})
 }
 
-func TestNext(t *testing.T) {
+func TestNewMultiset(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Next: root-only tree", func(t *testing.T) {
+	t.Run("NewMultiset: duplicates", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			root: &RBNode[int]{
-				Val:     20,
-				isBlack: true,
-			},
+		rbt := NewMultiset(cmp.Compare[int])
+
+		for _, val := range []int{5, 3, 5, 8, 3, 5} {
+			if _, ok := rbt.Insert(val); !ok {
+				t.Fail()
+			}
 		}
 
-		node, ok := rbt.root.Next()
-		if ok || node != nil {
+		if rbt.Count != 6 || rbt.Len() != 6 || !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), []int{3, 3, 5, 5, 5, 8}) {
 			t.Fail()
 		}
-	})
 
-	t.Run("Next: is a right child, has a bigger grandparent", func(t *testing.T) {
-		t.Parallel()
+		if !slices.Equal(slices.Collect(rbt.All()), rbt.ToSlice()) || !slices.Equal(slices.Collect(rbt.Backward()), []int{8, 5, 5, 5, 3, 3}) {
+			t.Fail()
+		}
 
-		rbt := initRBTBefore()
+		if !slices.Equal(slices.Collect(rbt.Range(4, 8)), []int{5, 5, 5, 8}) || len(slices.Collect(rbt.Nodes())) != 3 {
+			t.Fail()
+		}
 
-		node, ok := rbt.root.left.right.Next()
-		if !ok || node != rbt.root {
+		if rbn, _ := rbt.Find(5); rbn.Multiplicity() != 3 {
+			t.Fail()
+		}
+	})
+
+	t.Run("NewMultiset: delete", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int], WithInsertionOrder[int]())
+		rbt.InsertAll(1, 2, 2, 3, 3, 3)
+
+		if val, ok := rbt.Delete(3); !ok || val != 3 || rbt.Count != 5 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Delete(1); !ok || rbt.Contains(1) || rbt.Min.Val != 2 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		if val, ok := rbt.PopMax(); !ok || val != 3 || rbt.Max.Val != 3 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		if !slices.Equal(rbt.ToSlice(), []int{2, 2, 3}) || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{2, 3}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("NewMultiset: order statistics", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(10, 20, 20, 20, 30)
+
+		for i, expected := range []int{10, 20, 20, 20, 30} {
+			if val, ok := rbt.At(i); !ok || val != expected {
+				t.Fail()
+			}
+		}
+
+		if rank, ok := rbt.Rank(30); !ok || rank != 4 {
+			t.Fail()
+		}
+
+		if count, _, _ := rbt.Between(20, 30); count != 4 || rbt.CountBetween(10, 30) != 3 {
+			t.Fail()
+		}
+	})
+
+	t.Run("NewMultiset: mode", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 2, 2, 3, 3)
+
+		if mode, count, ok := rbt.Mode(); !ok || mode != 2 || count != 2 {
+			t.Fail()
+		}
+	})
+
+	t.Run("NewMultiset: random operations", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		counts := make(map[int]int)
+		total := 0
+
+		for range 5000 {
+			val := rand.IntN(50)
+
+			if rand.IntN(3) == 0 {
+				if _, ok := rbt.Delete(val); ok != (counts[val] > 0) {
+					t.FailNow()
+				}
+
+				if counts[val] > 0 {
+					counts[val]--
+					total--
+				}
+			} else {
+				_, _ = rbt.Insert(val)
+				counts[val]++
+				total++
+			}
+
+			if rbt.Count != total || !rbt.IsValid() {
+				t.FailNow()
+			}
+		}
+
+		if clone := rbt.Clone(); !clone.EqualTo(rbt) || clone.Count != total || !clone.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Clone: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+
+		rbtCloned := rbt.Clone()
+
+		if rbtCloned.root != nil || rbtCloned.Count != 0 || rbtCloned.cmp == nil || rbtCloned.Max != nil || rbtCloned.Min != nil {
+			t.Fail()
+		}
+	})
+
+	t.Run("Clone: big tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for range 10000 {
+			_, _ = rbt.Insert(rand.IntN(100000))
+		}
+
+		rbtCloned := rbt.Clone()
+
+		if !rbtCloned.EqualTo(rbt) || !rbtCloned.IsValid() || rbtCloned.Min == rbt.Min || rbtCloned.StructuralHash() != rbt.StructuralHash() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Clone: 3-node tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			root: &RBNode[int]{
+				Val:     70,
+				isBlack: true,
+			},
+			cmp:   cmp.Compare[int],
+			Count: 3,
+		}
+
+		rbt.root.left = &RBNode[int]{
+			Val:     50,
+			isBlack: true,
+			parent:  rbt.root,
+		}
+
+		rbt.root.right = &RBNode[int]{
+			Val:     80,
+			isBlack: true,
+			parent:  rbt.root,
+		}
+
+		rbt.Max = rbt.root.right
+		rbt.Min = rbt.root.left
+
+		rbtCloned := rbt.Clone()
+
+		if rbtCloned.root == nil || rbtCloned.Count != rbt.Count {
+			t.Fail()
+		}
+
+		if rbtCloned.cmp(rbtCloned.Min.Val, rbt.Min.Val) != 0 || rbtCloned.cmp(rbtCloned.Max.Val, rbt.Max.Val) != 0 {
+			t.Fail()
+		}
+
+		if rbtCloned.root.left == nil || rbtCloned.root.left.parent != rbtCloned.root {
+			t.Fail()
+		}
+
+		if rbtCloned.root.right == nil || rbtCloned.root.right.parent != rbtCloned.root {
+			t.Fail()
+		}
+
+		if rbtCloned.cmp(rbtCloned.root.Val, rbt.root.Val) != 0 {
+			t.Fail()
+		}
+
+		if rbtCloned.cmp(rbtCloned.root.left.Val, rbt.root.left.Val) != 0 {
+			t.Fail()
+		}
+
+		if rbtCloned.cmp(rbtCloned.root.right.Val, rbt.root.right.Val) != 0 {
+			t.Fail()
+		}
+
+		if rbtCloned.root.isBlack != rbt.root.isBlack {
+			t.Fail()
+		}
+
+		if rbtCloned.root.left.isBlack != rbt.root.left.isBlack {
+			t.Fail()
+		}
+
+		if rbtCloned.root.right.isBlack != rbt.root.right.isBlack {
+			t.Fail()
+		}
+	})
+}
+
+func TestSubtree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Subtree: nil node", func(t *testing.T) {
+		t.Parallel()
+
+		subtree := initRBTBefore().Subtree(nil)
+		if subtree.Count != 0 || !subtree.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Subtree: red subtree root", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		subtree := rbt.Subtree(rbt.root.left)
+
+		if subtree.Count != 3 || !subtree.IsValid() || subtree.Min.Val != 20 || subtree.Max.Val != 60 {
+			t.Fail()
+		}
+
+		if rbt.root.left.isBlack || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Subtree: insertion order", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+
+		for _, val := range []int{5, 9, 1, 3, 7, 2, 8} {
+			_, _ = rbt.Insert(val)
+		}
+
+		subtree := rbt.Subtree(rbt.root.left)
+
+		if !subtree.IsValid() || !slices.Equal(slices.Collect(subtree.InsertionOrder()), []int{1, 3, 2}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestEqualTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EqualTo: empty trees", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+		anotherRBT := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+
+		if !rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: empty and non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+		anotherRBT := initRBTBefore()
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: nil and non-nil tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+
+		var anotherRBT *RBTree[int]
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: non-equal structure", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		anotherRBT := initRBTBefore()
+
+		rbt.root.left.left.right = &RBNode[int]{
+			Val:     10,
+			isBlack: false,
+			parent:  rbt.root.left.left,
+		}
+
+		anotherRBT.root.right.right.right = &RBNode[int]{
+			Val:     110,
+			isBlack: false,
+			parent:  rbt.root.right.right,
+		}
+
+		rbt.Count++
+		anotherRBT.Count++
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: non-equal structure - left node", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		anotherRBT := initRBTBefore()
+
+		anotherRBT.root.left.left.left = &RBNode[int]{
+			Val:     10,
+			isBlack: false,
+			parent:  anotherRBT.root.left.left,
+		}
+
+		rbt.root.right.right.right = &RBNode[int]{
+			Val:     110,
+			isBlack: false,
+			parent:  rbt.root.right.right,
+		}
+
+		rbt.Count++
+		anotherRBT.Count++
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: non-equal structure - right node", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		anotherRBT := initRBTBefore()
+
+		anotherRBT.root.left.left.right = &RBNode[int]{
+			Val:     10,
+			isBlack: false,
+			parent:  anotherRBT.root.left.left,
+		}
+
+		rbt.root.right.right.right = &RBNode[int]{
+			Val:     110,
+			isBlack: false,
+			parent:  rbt.root.right.right,
+		}
+
+		rbt.Count++
+		anotherRBT.Count++
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: different size", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		anotherRBT := initRBTBefore()
+
+		rbt.root.left.left.right = &RBNode[int]{
+			Val:     10,
+			isBlack: false,
+			parent:  rbt.root.left.left,
+		}
+
+		rbt.Count++
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: different values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		anotherRBT := initRBTBefore()
+
+		anotherRBT.root.Val = 75
+
+		if rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualTo: equal trees", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		anotherRBT := initRBTBefore()
+
+		if !rbt.EqualTo(anotherRBT) {
+			t.Fail()
+		}
+	})
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	t.Run("String: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+
+		if rbt.String() != "" {
+			t.Fail()
+		}
+	})
+
+	t.Run("String: non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		expectedResult := "   100\n  80\n   75\n 70\n   60\n  50\n   20\n"
+
+		if rbt.String() != expectedResult {
+			t.Fail()
+		}
+	})
+
+	t.Run("StringIndent: default width", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.StringIndent(1) != rbt.String() {
+			t.Fail()
+		}
+	})
+
+	t.Run("StringIndent: wide indentation", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		expectedResult := "         100\n     80\n         75\n 70\n         60\n     50\n         20\n"
+
+		if rbt.StringIndent(4) != expectedResult {
+			t.Fail()
+		}
+	})
+
+	t.Run("StringIndent: zero width", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		expectedResult := "100\n80\n75\n70\n60\n50\n20\n"
+
+		if rbt.StringIndent(0) != expectedResult || rbt.StringIndent(-2) != expectedResult {
+			t.Fail()
+		}
+	})
+}
+
+func TestStringFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StringFunc: default format", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.StringFunc(func(val int) string { return fmt.Sprint(val) }) != rbt.String() {
+			t.Fail()
+		}
+	})
+
+	t.Run("StringFunc: custom format", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		expectedResult := "   <100>\n  <80>\n   <75>\n <70>\n   <60>\n  <50>\n   <20>\n"
+
+		if rbt.StringFunc(func(val int) string { return fmt.Sprintf("<%d>", val) }) != expectedResult {
+			t.Fail()
+		}
+	})
+
+	t.Run("StringIndentFunc: wide indentation", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		expectedResult := "     100\n   80\n     75\n 70\n     60\n   50\n     20\n"
+
+		if rbt.StringIndentFunc(2, strconv.Itoa) != expectedResult || NewOrdered[int]().StringIndentFunc(2, strconv.Itoa) != "" {
+			t.Fail()
+		}
+	})
+}
+
+func TestPrettyPrint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PrettyPrint: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if NewOrdered[int]().PrettyPrint() != "" {
+			t.Fail()
+		}
+	})
+
+	t.Run("PrettyPrint: non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		expectedResult := "70 (B)\n" +
+			"├── 50 (R)\n" +
+			"│   ├── 20 (B)\n" +
+			"│   └── 60 (B)\n" +
+			"└── 80 (R)\n" +
+			"    ├── 75 (B)\n" +
+			"    └── 100 (B)\n"
+
+		if rbt.PrettyPrint() != expectedResult {
+			t.Fail()
+		}
+	})
+
+	t.Run("PrettyPrint: one child", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for _, val := range []int{2, 1, 3, 4} {
+			_, _ = rbt.Insert(val)
+		}
+
+		expectedResult := "2 (B)\n" +
+			"├── 1 (B)\n" +
+			"└── 3 (B)\n" +
+			"    ├── nil\n" +
+			"    └── 4 (R)\n"
+
+		if rbt.PrettyPrint() != expectedResult {
+			t.Fail()
+		}
+	})
+
+	t.Run("PrettyPrint: deep tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(rbt.PrettyPrint(), "\n"), "\n")
+		if len(lines) < rbt.Count {
+			t.FailNow()
+		}
+
+		for _, line := range lines {
+			if i := strings.IndexAny(line, "├└"); i >= 0 && len([]rune(line[:i]))%4 != 0 {
+				t.Fail()
+			}
+		}
+	})
+}
+
+func TestToDOT(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ToDOT: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if NewOrdered[int]().ToDOT() != "digraph RBTree {\n\tnode [style=filled, fontcolor=white];\n}\n" {
+			t.Fail()
+		}
+	})
+
+	t.Run("ToDOT: non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+		_, _ = rbt.Insert(2)
+		_, _ = rbt.Insert(1)
+		expectedResult := "digraph RBTree {\n" +
+			"\tnode [style=filled, fontcolor=white];\n" +
+			"\tn0 [label=\"2\", fillcolor=black];\n" +
+			"\tn1 [label=\"1\", fillcolor=red];\n" +
+			"\tn2 [label=\"\", shape=box, width=0.1, height=0.1, fillcolor=black];\n" +
+			"\tn1 -> n2;\n" +
+			"\tn3 [label=\"\", shape=box, width=0.1, height=0.1, fillcolor=black];\n" +
+			"\tn1 -> n3;\n" +
+			"\tn0 -> n1;\n" +
+			"\tn4 [label=\"\", shape=box, width=0.1, height=0.1, fillcolor=black];\n" +
+			"\tn0 -> n4;\n" +
+			"}\n"
+
+		if rbt.ToDOT() != expectedResult {
+			t.Fail()
+		}
+	})
+
+	t.Run("ToDOT: deterministic", func(t *testing.T) {
+		t.Parallel()
+
+		if initRBTBefore().ToDOT() != initRBTBefore().ToDOT() || !strings.Contains(initRBTBefore().ToDOT(), "[label=\"50\", fillcolor=red]") {
+			t.Fail()
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Next: root-only tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			root: &RBNode[int]{
+				Val:     20,
+				isBlack: true,
+			},
+		}
+
+		node, ok := rbt.root.Next()
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+
+	t.Run("Next: is a right child, has a bigger grandparent", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		node, ok := rbt.root.left.right.Next()
+		if !ok || node != rbt.root {
 			t.Fail()
 		}
 	})
@@ -491,160 +1169,2539 @@ func TestNext(t *testing.T) {
 	t.Run("Next: is a right child", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
+		rbt := initRBTBefore()
+
+		node, ok := rbt.root.right.right.Next()
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestPrev(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Prev: root-only tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			root: &RBNode[int]{
+				Val:     20,
+				isBlack: true,
+			},
+		}
+
+		node, ok := rbt.root.Prev()
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+
+	t.Run("Prev: is a left child, has a smaller grandparent", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		node, ok := rbt.root.right.left.Prev()
+		if !ok || node != rbt.root {
+			t.Fail()
+		}
+	})
+
+	t.Run("Prev: is a left child", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		node, ok := rbt.root.left.left.Prev()
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestSibling(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sibling: root", func(t *testing.T) {
+		t.Parallel()
+
+		node, ok := initRBTBefore().root.Sibling()
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+
+	t.Run("Sibling: left and right children", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		left, leftOK := rbt.root.left.Sibling()
+		right, rightOK := rbt.root.right.Sibling()
+
+		if !leftOK || left != rbt.root.right || !rightOK || right != rbt.root.left {
+			t.Fail()
+		}
+	})
+
+	t.Run("Sibling: only child", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		node, _ := rbt.Insert(10)
+
+		sibling, ok := node.Sibling()
+		if ok || sibling != nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestUncle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Uncle: root and its children", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if _, ok := rbt.root.Uncle(); ok {
+			t.Fail()
+		}
+
+		if _, ok := rbt.root.left.Uncle(); ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("Uncle: grandchildren", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		left, leftOK := rbt.root.left.right.Uncle()
+		right, rightOK := rbt.root.right.left.Uncle()
+
+		if !leftOK || left != rbt.root.right || !rightOK || right != rbt.root.left {
+			t.Fail()
+		}
+	})
+
+	t.Run("Uncle: grandparent with one child", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		rbt.root.right = nil
+
+		if uncle, ok := rbt.root.left.left.Uncle(); ok || uncle != nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Clear: insert after clear", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Clear()
+
+		if !rbt.IsEmpty() || rbt.Count != 0 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		for _, val := range []int{3, 1, 2} {
+			_, _ = rbt.Insert(val)
+		}
+
+		if !rbt.IsValid() || !slices.Equal(slices.Collect(rbt.All()), []int{1, 2, 3}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Clear: options are kept", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+		_, _ = rbt.Insert(1)
+		rbt.Clear()
+		_, _ = rbt.Insert(2)
+
+		if !rbt.IsValid() || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{2}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestIsEmptyLen(t *testing.T) {
+	t.Parallel()
+
+	rbt := NewOrdered[int]()
+
+	if !rbt.IsEmpty() || rbt.Len() != 0 {
+		t.Fail()
+	}
+
+	rbt = initRBTBefore()
+
+	if rbt.IsEmpty() || rbt.Len() != 7 {
+		t.Fail()
+	}
+}
+
+func TestGetOrInsert(t *testing.T) {
+	t.Parallel()
+
+	type pair struct {
+		key   int
+		value string
+	}
+
+	comparePairs := func(a, b pair) int {
+		return cmp.Compare(a.key, b.key)
+	}
+
+	t.Run("GetOrInsert: new and existing values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbn, ok := rbt.GetOrInsert(65); !ok || rbn.Val != 65 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		if rbn, ok := rbt.GetOrInsert(50); ok || rbn != rbt.root.left || rbt.Count != 8 {
+			t.Fail()
+		}
+	})
+
+	t.Run("GetOrInsertFunc: composite values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := New(comparePairs)
+		calls := 0
+		build := func(key int) func() pair {
+			return func() pair {
+				calls++
+
+				return pair{key, strconv.Itoa(key)}
+			}
+		}
+
+		for _, key := range []int{5, 3, 8, 1, 4} {
+			if rbn, ok := rbt.GetOrInsertFunc(pair{key: key}, build(key)); !ok || rbn.Val.value != strconv.Itoa(key) || !rbt.IsValid() {
+				t.FailNow()
+			}
+		}
+
+		if rbn, ok := rbt.GetOrInsertFunc(pair{key: 3}, build(3)); ok || rbn.Val.value != "3" || calls != 5 || rbt.Count != 5 {
+			t.Fail()
+		}
+
+		if rbt.Min.Val.key != 1 || rbt.Max.Val.key != 8 {
+			t.Fail()
+		}
+	})
+
+	t.Run("GetOrInsertFunc: max count", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := New(comparePairs, WithMaxCount[pair](2, EvictMin))
+
+		for _, key := range []int{1, 2, 3} {
+			_, _ = rbt.GetOrInsertFunc(pair{key: key}, func() pair { return pair{key, "new"} })
+		}
+
+		if rbn, ok := rbt.GetOrInsertFunc(pair{key: 3}, func() pair { return pair{3, "replaced"} }); ok || rbn.Val.value != "new" {
+			t.Fail()
+		}
+
+		if rbt.Count != 2 || rbt.Min.Val.key != 2 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("GetOrInsert: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		inserted, _ := rbt.Insert(5)
+
+		if rbn, ok := rbt.GetOrInsert(5); ok || rbn != inserted || rbn.Multiplicity() != 1 || rbt.Count != 1 {
+			t.Fail()
+		}
+
+		if rbn, ok := rbt.GetOrInsert(6); !ok || rbn.Val != 6 || rbt.Count != 2 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("GetOrInsertFunc: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(comparePairs)
+		inserted, _ := rbt.Insert(pair{1, "a"})
+
+		rbn, ok := rbt.GetOrInsertFunc(pair{key: 1}, func() pair { return pair{1, "b"} })
+		if ok || rbn != inserted || rbn.Val.value != "a" || rbn.Multiplicity() != 1 || rbt.Count != 1 {
+			t.Fail()
+		}
+	})
+
+	t.Run("GetOrInsertFunc: frozen tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := New(comparePairs)
+		rbt.Freeze()
+
+		defer func() {
+			if recover() == nil {
+				t.Fail()
+			}
+		}()
+
+		_, _ = rbt.GetOrInsertFunc(pair{key: 1}, func() pair { return pair{key: 1} })
+	})
+}
+
+func TestInsertOrReplace(t *testing.T) {
+	t.Parallel()
+
+	type pair struct {
+		key   int
+		value string
+	}
+
+	t.Run("InsertOrReplace: new value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbn, ok := rbt.InsertOrReplace(65); !ok || rbn.Val != 65 || rbt.Count != 8 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertOrReplace: existing key", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := New(func(a, b pair) int { return cmp.Compare(a.key, b.key) })
+		_, _ = rbt.Insert(pair{1, "a"})
+		inserted, _ := rbt.Insert(pair{2, "b"})
+
+		rbn, ok := rbt.InsertOrReplace(pair{2, "c"})
+		if ok || rbn != inserted || rbn.Val.value != "c" || rbt.Count != 2 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertOrReplace: evicted value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithMaxCount[int](1, EvictMin))
+		_, _ = rbt.Insert(5)
+
+		if rbn, ok := rbt.InsertOrReplace(1); ok || rbn != nil || rbt.Min.Val != 5 {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertOrReplace: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(func(a, b pair) int { return cmp.Compare(a.key, b.key) })
+		_, _ = rbt.Insert(pair{1, "a"})
+		_, _ = rbt.Insert(pair{1, "a"})
+
+		rbn, ok := rbt.InsertOrReplace(pair{1, "b"})
+		if ok || rbn.Val.value != "b" || rbn.Multiplicity() != 2 || rbt.Count != 2 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestInsertAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("InsertAll: new and duplicate values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.InsertAll(10, 70, 65, 10, 200) != 3 || !rbt.IsValid() || rbt.Count != 10 || rbt.Min.Val != 10 || rbt.Max.Val != 200 {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertAll: no values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		if rbt.InsertAll() != 0 || !rbt.IsEmpty() {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertAll: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		_, _ = rbt.Insert(5)
+
+		if rbt.InsertAll(5, 5, 6) != 3 || rbt.Count != 4 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+
+	rbt := initRBTBefore()
+
+	if !rbt.Contains(20) || !rbt.Contains(100) || rbt.Contains(65) {
+		t.Fail()
+	}
+
+	if NewOrdered[int]().Contains(0) || (&RBTree[int]{}).Contains(0) {
+		t.Fail()
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Find: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		node, ok := (&RBTree[int]{}).Find(10)
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+
+	t.Run("Find: non-existent value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := &RBTree[int]{
+			root: &RBNode[int]{
+				Val:     20,
+				isBlack: true,
+			},
+			cmp: cmp.Compare[int],
+		}
+
+		node, ok := rbt.Find(10)
+		if ok || node != nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestPathTo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PathTo: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		path, ok := NewOrdered[int]().PathTo(10)
+		if ok || path == nil || len(path) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("PathTo: non-existent value", func(t *testing.T) {
+		t.Parallel()
+
+		path, ok := initRBTBefore().PathTo(65)
+		if ok || path == nil || len(path) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("PathTo: leaf", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		path, ok := rbt.PathTo(60)
+		if !ok || !slices.Equal(path, []*RBNode[int]{rbt.root, rbt.root.left, rbt.root.left.right}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("PathTo: root", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		path, ok := rbt.PathTo(70)
+		if !ok || !slices.Equal(path, []*RBNode[int]{rbt.root}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Insert: existent value", func(t *testing.T) {
+		t.Parallel()
+
+		const val = 75
+
+		rbt := initRBTBefore()
+
+		existentNode, ok := rbt.Insert(val)
+		if ok || !rbt.IsValid() || existentNode == nil || rbt.cmp(existentNode.Val, val) != 0 {
+			t.Fail()
+		}
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delete: the last node", func(t *testing.T) {
+		t.Parallel()
+
+		const val = 50
+
+		rbtBefore := &RBTree[int]{
+			root: &RBNode[int]{
+				Val:     val,
+				isBlack: true,
+			},
+			cmp:   cmp.Compare[int],
+			Count: 1,
+		}
+
+		rbtBefore.Max = rbtBefore.root
+		rbtBefore.Min = rbtBefore.root
+
+		_, ok := rbtBefore.Delete(val)
+		if !ok || !rbtBefore.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Delete: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbtBefore := &RBTree[int]{
+			cmp: cmp.Compare[int],
+		}
+
+		_, ok := rbtBefore.Delete(10)
+		if ok || !rbtBefore.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Delete: non-existent value", func(t *testing.T) {
+		t.Parallel()
+
+		rbtBefore := &RBTree[int]{
+			root: &RBNode[int]{
+				Val:     20,
+				isBlack: true,
+				size:    1,
+			},
+			cmp:   cmp.Compare[int],
+			Count: 1,
+		}
+
+		rbtBefore.Min = rbtBefore.root
+		rbtBefore.Max = rbtBefore.root
+
+		_, ok := rbtBefore.Delete(30)
+		if ok || !rbtBefore.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Rotate: nil node", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.RotateLeft(nil) != ErrNilNode || rbt.RotateRight(nil) != ErrNilNode {
+			t.Fail()
+		}
+	})
+
+	t.Run("Rotate: leaf", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.RotateLeft(rbt.Min) != ErrNoRightChild || rbt.RotateRight(rbt.Min) != ErrNoLeftChild {
+			t.Fail()
+		}
+
+		if !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Rotate: left and back", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		root := rbt.root
+
+		if rbt.RotateLeft(root) != nil || rbt.root.Val != 80 || rbt.root.left != root || rbt.IsValid() {
+			t.Fail()
+		}
+
+		if rbt.RotateRight(rbt.root) != nil || !rbt.IsValid() || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+}
+
+func TestMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Mode: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		_, count, ok := NewOrdered[int]().Mode()
+		if ok || count != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Mode: ties", func(t *testing.T) {
+		t.Parallel()
+
+		mode, count, ok := initRBTBefore().Mode()
+		if !ok || mode != 20 || count != 1 {
+			t.Fail()
+		}
+	})
+}
+
+func TestWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	rbt := NewOrdered[string]()
+
+	for _, val := range []string{"car", "cat", "catalog", "cattle", "dog", "ca", "cb"} {
+		_, _ = rbt.Insert(val)
+	}
+
+	t.Run("WithPrefix: matching values", func(t *testing.T) {
+		t.Parallel()
+
+		if !slices.Equal(slices.Collect(WithPrefix(rbt, "cat")), []string{"cat", "catalog", "cattle"}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("WithPrefix: empty prefix", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(WithPrefix(rbt, ""))) != rbt.Count {
+			t.Fail()
+		}
+	})
+
+	t.Run("WithPrefix: prefix beyond all values", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(WithPrefix(rbt, "z"))) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("WithPrefix: early break", func(t *testing.T) {
+		t.Parallel()
+
+		for val := range WithPrefix(rbt, "c") {
+			if val != "ca" {
+				t.Fail()
+			}
+
+			break
+		}
+	})
+
+	t.Run("WithPrefix: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(WithPrefix(NewOrdered[string](), "c"))) != 0 {
+			t.Fail()
+		}
+	})
+}
+
+func TestRangeFunc(t *testing.T) {
+	t.Parallel()
+
+	even := func(val int) bool {
+		return val%20 == 0
+	}
+
+	t.Run("RangeFunc: filtered range", func(t *testing.T) {
+		t.Parallel()
+
+		if !slices.Equal(slices.Collect(initRBTBefore().RangeFunc(50, 100, even)), []int{60, 80, 100}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("RangeFunc: lo > hi", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(initRBTBefore().RangeFunc(100, 50, even))) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("RangeFunc: early break", func(t *testing.T) {
+		t.Parallel()
+
+		var vals []int
+
+		for val := range initRBTBefore().RangeFunc(0, 100, even) {
+			vals = append(vals, val)
+
+			break
+		}
+
+		if !slices.Equal(vals, []int{20}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("All: ascending order", func(t *testing.T) {
+		t.Parallel()
+
+		if !slices.Equal(slices.Collect(initRBTBefore().All()), []int{20, 50, 60, 70, 75, 80, 100}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("All: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(NewOrdered[int]().All())) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("All: early break", func(t *testing.T) {
+		t.Parallel()
+
+		var vals []int
+
+		for val := range initRBTBefore().All() {
+			if val > 60 {
+				break
+			}
+
+			vals = append(vals, val)
+		}
+
+		if !slices.Equal(vals, []int{20, 50, 60}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestBackward(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Backward: descending order", func(t *testing.T) {
+		t.Parallel()
+
+		if !slices.Equal(slices.Collect(initRBTBefore().Backward()), []int{100, 80, 75, 70, 60, 50, 20}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Backward: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(NewOrdered[int]().Backward())) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Backward: early break", func(t *testing.T) {
+		t.Parallel()
+
+		var vals []int
+
+		for val := range initRBTBefore().Backward() {
+			if val < 75 {
+				break
+			}
+
+			vals = append(vals, val)
+		}
+
+		if !slices.Equal(vals, []int{100, 80, 75}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestNodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nodes: ascending order and mutation", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		for rbn := range rbt.Nodes() {
+			rbn.Val++
+		}
+
+		if !rbt.IsValid() || !slices.Equal(slices.Collect(rbt.All()), []int{21, 51, 61, 71, 76, 81, 101}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("NodesBackward: descending order and early break", func(t *testing.T) {
+		t.Parallel()
+
+		var nodes []*RBNode[int]
+
+		rbt := initRBTBefore()
+
+		for rbn := range rbt.NodesBackward() {
+			if rbn.Val < 75 {
+				break
+			}
+
+			nodes = append(nodes, rbn)
+		}
+
+		if len(nodes) != 3 || nodes[0] != rbt.Max || nodes[2] != rbt.root.right.left {
+			t.Fail()
+		}
+	})
+
+	t.Run("Nodes: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		if len(slices.Collect(rbt.Nodes())) != 0 || len(slices.Collect(rbt.NodesBackward())) != 0 {
+			t.Fail()
+		}
+	})
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		lo, hi   int
+		expected []int
+	}{
+		{"Range: inclusive bounds", 50, 80, []int{50, 60, 70, 75, 80}},
+		{"Range: bounds between values", 55, 77, []int{60, 70, 75}},
+		{"Range: whole tree", 0, 200, []int{20, 50, 60, 70, 75, 80, 100}},
+		{"Range: single value", 75, 75, []int{75}},
+		{"Range: no values", 61, 69, []int{}},
+		{"Range: lo > hi", 80, 50, []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vals := []int{}
+
+			for val := range initRBTBefore().Range(tc.lo, tc.hi) {
+				vals = append(vals, val)
+			}
+
+			if !slices.Equal(vals, tc.expected) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Range: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if len(slices.Collect(NewOrdered[int]().Range(0, 10))) != 0 {
+			t.Fail()
+		}
+	})
+}
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ForEach: all nodes", func(t *testing.T) {
+		t.Parallel()
+
+		var vals []int
+
+		initRBTBefore().ForEach(func(rbn *RBNode[int]) bool {
+			vals = append(vals, rbn.Val)
+
+			return true
+		})
+
+		if !slices.Equal(vals, []int{20, 50, 60, 70, 75, 80, 100}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ForEach: early stop", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		initRBTBefore().ForEach(func(rbn *RBNode[int]) bool {
+			calls++
+
+			return rbn.Val < 60
+		})
+
+		if calls != 3 {
+			t.Fail()
+		}
+	})
+
+	t.Run("ForEach: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		NewOrdered[int]().ForEach(func(*RBNode[int]) bool {
+			t.Fail()
+
+			return true
+		})
+	})
+
+	t.Run("ForEachRange: range", func(t *testing.T) {
+		t.Parallel()
+
+		var vals []int
+
+		initRBTBefore().ForEachRange(55, 80, func(rbn *RBNode[int]) bool {
+			vals = append(vals, rbn.Val)
+
+			return true
+		})
+
+		if !slices.Equal(vals, []int{60, 70, 75, 80}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ForEachRange: early stop", func(t *testing.T) {
+		t.Parallel()
+
+		var vals []int
+
+		initRBTBefore().ForEachRange(50, 100, func(rbn *RBNode[int]) bool {
+			vals = append(vals, rbn.Val)
+
+			return len(vals) < 2
+		})
+
+		if !slices.Equal(vals, []int{50, 60}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ForEachRange: empty range", func(t *testing.T) {
+		t.Parallel()
+
+		initRBTBefore().ForEachRange(100, 50, func(*RBNode[int]) bool {
+			t.Fail()
+
+			return true
+		})
+	})
+}
+
+func TestForEachBatch(t *testing.T) {
+	t.Parallel()
+
+	collect := func(rbt *RBTree[int], size int) ([][]int, error) {
+		var batches [][]int
+
+		err := rbt.ForEachBatch(size, func(batch []int) error {
+			batches = append(batches, slices.Clone(batch))
+
+			return nil
+		})
+
+		return batches, err
+	}
+
+	testCases := []struct {
+		name     string
+		size     int
+		expected [][]int
+	}{
+		{"ForEachBatch: partial last batch", 3, [][]int{{20, 50, 60}, {70, 75, 80}, {100}}},
+		{"ForEachBatch: full batches", 7, [][]int{{20, 50, 60, 70, 75, 80, 100}}},
+		{"ForEachBatch: batch bigger than tree", 10, [][]int{{20, 50, 60, 70, 75, 80, 100}}},
+		{"ForEachBatch: non-positive size", 0, [][]int{{20}, {50}, {60}, {70}, {75}, {80}, {100}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			batches, err := collect(initRBTBefore(), tc.size)
+			if err != nil || !slices.EqualFunc(batches, tc.expected, slices.Equal) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("ForEachBatch: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if batches, err := collect(NewOrdered[int](), 3); err != nil || len(batches) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("ForEachBatch: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 1, 2, 3, 3)
+
+		batches, err := collect(rbt, 4)
+		if err != nil || !slices.EqualFunc(batches, [][]int{{1, 1, 1, 2}, {3, 3}}, slices.Equal) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ForEachBatch: error stops the walk", func(t *testing.T) {
+		t.Parallel()
+
+		errStop := errors.New("stop")
+		calls := 0
+
+		err := initRBTBefore().ForEachBatch(2, func([]int) error {
+			calls++
+
+			return errStop
+		})
+
+		if !errors.Is(err, errStop) || calls != 1 {
+			t.Fail()
+		}
+	})
+}
+
+func TestInsertionOrder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("InsertionOrder: not tracked", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		_, oldestOK := rbt.Oldest()
+		_, newestOK := rbt.Newest()
+
+		if oldestOK || newestOK || len(slices.Collect(rbt.InsertionOrder())) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertionOrder: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+
+		_, oldestOK := rbt.Oldest()
+		_, newestOK := rbt.Newest()
+
+		if oldestOK || newestOK || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertionOrder: insert and delete", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+
+		for _, val := range []int{50, 20, 80, 10, 30, 70, 90, 60, 20} {
+			_, _ = rbt.Insert(val)
+		}
+
+		for _, val := range []int{50, 10, 90} {
+			_, _ = rbt.Delete(val)
+		}
+
+		oldest, oldestOK := rbt.Oldest()
+		newest, newestOK := rbt.Newest()
+
+		if !oldestOK || oldest.Val != 20 || !newestOK || newest.Val != 60 || !rbt.IsValid() {
+			t.Fail()
+		}
+
+		if !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{20, 80, 30, 70, 60}) {
+			t.Fail()
+		}
+
+		rbtCloned := rbt.Clone()
+
+		if !rbtCloned.IsValid() || !slices.Equal(slices.Collect(rbtCloned.InsertionOrder()), []int{20, 80, 30, 70, 60}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("InsertionOrder: random", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+
+		var order []int
+
+		for range 1000 {
+			val := rand.IntN(200)
+
+			if rand.IntN(3) == 0 {
+				if _, ok := rbt.Delete(val); ok {
+					order = slices.DeleteFunc(order, func(v int) bool { return v == val })
+				}
+			} else if _, ok := rbt.Insert(val); ok {
+				order = append(order, val)
+			}
+
+			if !rbt.IsValid() {
+				t.FailNow()
+			}
+		}
+
+		if !slices.Equal(slices.Collect(rbt.InsertionOrder()), order) {
+			t.Fail()
+		}
+	})
+}
+
+func TestBetween(t *testing.T) {
+	t.Parallel()
+
+	rbt := initRBTBefore()
+
+	testCases := []struct {
+		name        string
+		lo, hi      int
+		count       int
+		first, last int
+	}{
+		{"Between: inner range", 55, 90, 4, 60, 80},
+		{"Between: exact bounds", 50, 75, 4, 50, 75},
+		{"Between: whole tree", 0, 1000, 7, 20, 100},
+		{"Between: single value", 70, 70, 1, 70, 70},
+		{"Between: no values", 61, 69, 0, 0, 0},
+		{"Between: reversed bounds", 80, 50, 0, 0, 0},
+		{"Between: out of range", 101, 200, 0, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			count, first, last := rbt.Between(tc.lo, tc.hi)
+			if count != tc.count {
+				t.Fail()
+			}
+
+			if count == 0 && (first != nil || last != nil) {
+				t.Fail()
+			}
+
+			if count != 0 && (first.Val != tc.first || last.Val != tc.last) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Between: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		count, first, last := NewOrdered[int]().Between(0, 10)
+		if count != 0 || first != nil || last != nil {
+			t.Fail()
+		}
+	})
+
+	t.Run("Between: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 2, 2, 3, 3, 3, 5)
+
+		count, first, last := rbt.Between(2, 4)
+		if count != 5 || first.Val != 2 || last.Val != 3 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Between: one descent per bound", func(t *testing.T) {
+		t.Parallel()
+
+		compare, comparisons := CountingCmp(cmp.Compare[int])
+		rbt := New(compare)
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
+		}
+
+		atomic.StoreInt64(comparisons, 0)
+
+		if count, _, _ := rbt.Between(100, 899); count != 800 || atomic.LoadInt64(comparisons) > int64(2*rbt.Height()) {
+			t.Fail()
+		}
+	})
+}
+
+func TestMedianSplit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MedianSplit: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if _, ok := NewOrdered[int]().MedianSplit(); ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("MedianSplit: single node", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+		_, _ = rbt.Insert(5)
+
+		if median, ok := rbt.MedianSplit(); !ok || median != 5 {
+			t.Fail()
+		}
+	})
+
+	t.Run("MedianSplit: odd count", func(t *testing.T) {
+		t.Parallel()
+
+		if median, ok := initRBTBefore().MedianSplit(); !ok || median != 70 {
+			t.Fail()
+		}
+	})
+
+	t.Run("MedianSplit: even count", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		_, _ = rbt.Delete(100)
+
+		if median, ok := rbt.MedianSplit(); !ok || median != 70 {
+			t.Fail()
+		}
+	})
+}
+
+func TestWithinDistance(t *testing.T) {
+	t.Parallel()
+
+	rbt := initRBTBefore()
+
+	testCases := []struct {
+		name           string
+		center, radius int
+		expected       []int
+	}{
+		{"WithinDistance: inner values", 70, 10, []int{60, 70, 75, 80}},
+		{"WithinDistance: zero radius, present center", 75, 0, []int{75}},
+		{"WithinDistance: zero radius, absent center", 76, 0, nil},
+		{"WithinDistance: negative radius", 70, -10, nil},
+		{"WithinDistance: whole tree", 0, 1000, []int{20, 50, 60, 70, 75, 80, 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if !slices.Equal(slices.Collect(WithinDistance(rbt, tc.center, tc.radius)), tc.expected) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("WithinDistance: overflow", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[uint8]()
+
+		for _, val := range []uint8{0, 5, 250, 255} {
+			_, _ = rbt.Insert(val)
+		}
+
+		if !slices.Equal(slices.Collect(WithinDistance(rbt, 3, 10)), []uint8{0, 5}) {
+			t.Fail()
+		}
+
+		if !slices.Equal(slices.Collect(WithinDistance(rbt, 252, 10)), []uint8{250, 255}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Random: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if _, ok := NewOrdered[int]().Random(nil); ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("Random: seeded source", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		first := rand.New(rand.NewPCG(1, 2))
+		second := rand.New(rand.NewPCG(1, 2))
+
+		for range 100 {
+			firstVal, firstOK := rbt.Random(first)
+			secondVal, secondOK := rbt.Random(second)
+
+			if !firstOK || !secondOK || firstVal != secondVal {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("Random: all values are sampled", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		sampled := make(map[int]struct{})
+
+		for range 1000 {
+			val, ok := rbt.Random(nil)
+			if !ok {
+				t.FailNow()
+			}
+
+			if _, found := rbt.Find(val); !found {
+				t.FailNow()
+			}
+
+			sampled[val] = struct{}{}
+		}
+
+		if len(sampled) != rbt.Count {
+			t.Fail()
+		}
+	})
+}
+
+func TestFreeze(t *testing.T) {
+	t.Parallel()
+
+	expectFrozenPanic := func(t *testing.T, mutate func()) {
+		t.Helper()
+
+		defer func() {
+			if err, ok := recover().(error); !ok || !errors.Is(err, ErrFrozen) {
+				t.Fail()
+			}
+		}()
+
+		mutate()
+	}
+
+	t.Run("Freeze: reads", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Freeze()
+
+		if _, ok := rbt.Find(70); !ok || !rbt.IsValid() || !rbt.IsFrozen() || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Freeze: mutations", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Freeze()
+
+		expectFrozenPanic(t, func() { _, _ = rbt.Insert(10) })
+		expectFrozenPanic(t, func() { _, _ = rbt.Delete(70) })
+		expectFrozenPanic(t, func() { _, _ = rbt.PopMin() })
+		expectFrozenPanic(t, func() { _ = rbt.DeleteNode(rbt.root) })
+		expectFrozenPanic(t, func() { rbt.KeepFirst(1) })
+		expectFrozenPanic(t, rbt.Clear)
+
+		if !errors.Is(rbt.RotateLeft(rbt.root), ErrFrozen) || !errors.Is(rbt.RotateRight(rbt.root), ErrFrozen) {
+			t.Fail()
+		}
+
+		if !errors.Is(rbt.Transaction(func(*RBTree[int]) error { return nil }), ErrFrozen) {
+			t.Fail()
+		}
+
+		if !rbt.IsValid() || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Freeze: clone is mutable", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Freeze()
+
+		rbtCloned := rbt.Clone()
+
+		if _, ok := rbtCloned.Insert(10); !ok || rbtCloned.IsFrozen() || !rbtCloned.IsValid() {
+			t.Fail()
+		}
+	})
+}
+
+func TestSimulateDelete(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                  string
+		insert, delete        int
+		rotations, colorFlips int
+		found                 bool
+	}{
+		{"SimulateDelete: non-existent value", 0, 10, 0, 0, false},
+		{"SimulateDelete: black leaf with black sibling", 0, 20, 0, 2, true},
+		{"SimulateDelete: node with two children", 0, 70, 0, 2, true},
+		{"SimulateDelete: black leaf with red nephew", 65, 20, 1, 3, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt := initRBTBefore()
+
+			if tc.insert != 0 {
+				_, _ = rbt.Insert(tc.insert)
+			}
+
+			rbtBefore := rbt.Clone()
+
+			rotations, colorFlips, found := rbt.SimulateDelete(tc.delete)
+			if rotations != tc.rotations || colorFlips != tc.colorFlips || found != tc.found {
+				t.Fail()
+			}
+
+			if !rbt.EqualTo(rbtBefore) || rbt.counters != nil {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestMust(t *testing.T) {
+	t.Parallel()
+
+	expectPanic := func(t *testing.T, expected string, fn func()) {
+		t.Helper()
+
+		defer func() {
+			if msg, ok := recover().(string); !ok || msg != expected {
+				t.Fail()
+			}
+		}()
+
+		fn()
+	}
+
+	t.Run("MustInsert: new value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbn := rbt.MustInsert(10); rbn.Val != 10 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("MustInsert: existent value", func(t *testing.T) {
+		t.Parallel()
+
+		expectPanic(t, "rbtree: value 70 already exists", func() { initRBTBefore().MustInsert(70) })
+	})
+
+	t.Run("MustInsert: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.MustInsert(1)
+
+		expectPanic(t, "rbtree: value 1 already exists", func() { rbt.MustInsert(1) })
+
+		if rbt.Count != 1 {
+			t.Fail()
+		}
+	})
+
+	t.Run("MustDelete: existent value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if val := rbt.MustDelete(70); val != 70 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("MustDelete: non-existent value", func(t *testing.T) {
+		t.Parallel()
+
+		expectPanic(t, "rbtree: value 10 does not exist", func() { initRBTBefore().MustDelete(10) })
+	})
+}
+
+func TestIsMinIsMax(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IsMin, IsMax: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		if rbt.IsMin(0) || rbt.IsMax(0) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IsMin, IsMax: non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if !rbt.IsMin(20) || rbt.IsMin(50) || !rbt.IsMax(100) || rbt.IsMax(80) || rbt.IsMin(100) {
+			t.Fail()
+		}
+	})
+}
+
+func TestDeleteNode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DeleteNode: nil node", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.DeleteNode(nil) || rbt.Count != 7 {
+			t.Fail()
+		}
+	})
+
+	t.Run("DeleteNode: every node", func(t *testing.T) {
+		t.Parallel()
+
+		for _, val := range []int{20, 50, 60, 70, 75, 80, 100} {
+			rbt := initRBTBefore()
+			rbn, _ := rbt.Find(val)
+
+			if !rbt.DeleteNode(rbn) || !rbt.IsValid() || rbt.Count != 6 {
+				t.FailNow()
+			}
+
+			if _, ok := rbt.Find(val); ok {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("DeleteNode: random nodes", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for range 500 {
+			_, _ = rbt.Insert(rand.IntN(1000))
+		}
+
+		for rbt.Count > 0 {
+			rbn, _ := rbt.Select(rand.IntN(rbt.Count))
+
+			if !rbt.DeleteNode(rbn) || !rbt.IsValid() {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestDeletePtr(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DeletePtr: zero value", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+		_, _ = rbt.Insert(0)
+		_, _ = rbt.Insert(1)
+
+		del, ok := rbt.DeletePtr(0)
+		if !ok || del == nil || *del != 0 || rbt.Count != 1 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("DeletePtr: miss", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if del, ok := rbt.DeletePtr(0); ok || del != nil || rbt.Count != 7 {
+			t.Fail()
+		}
+	})
+}
+
+func TestPopMinPopMax(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PopMin, PopMax: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		if _, ok := rbt.PopMin(); ok {
+			t.Fail()
+		}
+
+		if _, ok := rbt.PopMax(); ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("PopMin, PopMax: drain the tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		var popped []int
+
+		for rbt.Count > 0 {
+			minVal, _ := rbt.PopMin()
+			popped = append(popped, minVal)
+
+			if maxVal, ok := rbt.PopMax(); ok {
+				popped = append(popped, maxVal)
+			}
+
+			if !rbt.IsValid() {
+				t.FailNow()
+			}
+		}
+
+		if !slices.Equal(popped, []int{20, 100, 50, 80, 60, 75, 70}) || rbt.Min != nil || rbt.Max != nil {
+			t.Fail()
+		}
+	})
+}
+
+func TestDeleteMinDeleteMax(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DeleteMin, DeleteMax: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		if rbt.DeleteMin() || rbt.DeleteMax() {
+			t.Fail()
+		}
+	})
+
+	t.Run("DeleteMin, DeleteMax: non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if !rbt.DeleteMin() || !rbt.DeleteMax() || !rbt.IsValid() || rbt.Count != 5 || rbt.Min.Val != 50 || rbt.Max.Val != 80 {
+			t.Fail()
+		}
+	})
+}
+
+func TestDeleteRangeFunc(t *testing.T) {
+	t.Parallel()
+
+	even := func(val int) bool {
+		return val%20 == 0
+	}
+
+	testCases := []struct {
+		name      string
+		lo, hi    int
+		deleted   int
+		remaining []int
+	}{
+		{"DeleteRangeFunc: filtered range", 50, 80, 2, []int{20, 50, 70, 75, 100}},
+		{"DeleteRangeFunc: whole tree", 0, 200, 4, []int{50, 70, 75}},
+		{"DeleteRangeFunc: lo > hi", 80, 50, 0, []int{20, 50, 60, 70, 75, 80, 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt := initRBTBefore()
+
+			if rbt.DeleteRangeFunc(tc.lo, tc.hi, even) != tc.deleted || !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), tc.remaining) {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestKeepFirstKeepLast(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		n           int
+		first, last []int
+	}{
+		{"KeepFirst, KeepLast: negative", -1, []int{}, []int{}},
+		{"KeepFirst, KeepLast: zero", 0, []int{}, []int{}},
+		{"KeepFirst, KeepLast: some", 3, []int{20, 50, 60}, []int{75, 80, 100}},
+		{"KeepFirst, KeepLast: Count", 7, []int{20, 50, 60, 70, 75, 80, 100}, []int{20, 50, 60, 70, 75, 80, 100}},
+		{"KeepFirst, KeepLast: more than Count", 10, []int{20, 50, 60, 70, 75, 80, 100}, []int{20, 50, 60, 70, 75, 80, 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			first, last := initRBTBefore(), initRBTBefore()
+			first.KeepFirst(tc.n)
+			last.KeepLast(tc.n)
+
+			if !first.IsValid() || !slices.Equal(slices.Collect(first.All()), tc.first) || first.Count != len(tc.first) {
+				t.Fail()
+			}
+
+			if !last.IsValid() || !slices.Equal(slices.Collect(last.All()), tc.last) || last.Count != len(tc.last) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("KeepFirst, KeepLast: multisets", func(t *testing.T) {
+		t.Parallel()
+
+		for _, n := range []int{1, 10, 500, 990} {
+			first := NewMultiset(cmp.Compare[int], WithInsertionOrder[int]())
+
+			for range 1000 {
+				_, _ = first.Insert(rand.IntN(300))
+			}
+
+			last := first.Clone()
+			vals := first.ToSlice()
+
+			first.KeepFirst(n)
+			last.KeepLast(n)
+
+			if !first.IsValid() || !slices.Equal(first.ToSlice(), vals[:n]) {
+				t.FailNow()
+			}
+
+			if !last.IsValid() || !slices.Equal(last.ToSlice(), vals[len(vals)-n:]) {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestPopMinN(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		n         int
+		popped    []int
+		remaining []int
+	}{
+		{"PopMinN: negative", -1, []int{}, []int{20, 50, 60, 70, 75, 80, 100}},
+		{"PopMinN: zero", 0, []int{}, []int{20, 50, 60, 70, 75, 80, 100}},
+		{"PopMinN: some", 3, []int{20, 50, 60}, []int{70, 75, 80, 100}},
+		{"PopMinN: all but one", 6, []int{20, 50, 60, 70, 75, 80}, []int{100}},
+		{"PopMinN: all", 7, []int{20, 50, 60, 70, 75, 80, 100}, []int{}},
+		{"PopMinN: more than Count", 10, []int{20, 50, 60, 70, 75, 80, 100}, []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt := initRBTBefore()
+
+			popped := rbt.PopMinN(tc.n)
+
+			remaining := []int{}
+			for rbn := rbt.Min; rbn != nil; rbn = rbn.nextNode() {
+				remaining = append(remaining, rbn.Val)
+			}
+
+			if !rbt.IsValid() || rbt.Count != len(tc.remaining) || !slices.Equal(popped, tc.popped) || !slices.Equal(remaining, tc.remaining) {
+				t.Fail()
+			}
+
+			if rbt.Count > 0 && (rbt.Min.Val != tc.remaining[0] || rbt.Max.Val != 100) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("PopMinN: insertion order", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered(WithInsertionOrder[int]())
+
+		for _, val := range []int{5, 1, 4, 2, 3} {
+			_, _ = rbt.Insert(val)
+		}
+
+		popped := rbt.PopMinN(2)
+
+		if !rbt.IsValid() || !slices.Equal(popped, []int{1, 2}) || !slices.Equal(slices.Collect(rbt.InsertionOrder()), []int{5, 4, 3}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("PopMinN: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		for n, expected := range map[int][]int{2: {1, 1}, 4: {1, 1, 1, 2}, 6: {1, 1, 1, 2, 3, 3}} {
+			rbt := NewMultiset(cmp.Compare[int])
+			rbt.InsertAll(1, 1, 1, 2, 3, 3)
+
+			if popped := rbt.PopMinN(n); !slices.Equal(popped, expected) || rbt.Count != 6-n || !rbt.IsValid() {
+				t.Fail()
+			}
+		}
+	})
+
+	t.Run("PopMinN: relinked nodes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, n := range []int{1, 10, 500, 999} {
+			rbt := NewMultiset(cmp.Compare[int], WithInsertionOrder[int]())
+
+			for range 1000 {
+				_, _ = rbt.Insert(rand.IntN(300))
+			}
+
+			vals := rbt.ToSlice()
+			order := slices.DeleteFunc(slices.Collect(rbt.InsertionOrder()), func(val int) bool { return val < vals[n] })
+			kept, _ := rbt.Find(vals[len(vals)-1])
+
+			popped := rbt.PopMinN(n)
+
+			if !rbt.IsValid() || !slices.Equal(popped, vals[:n]) || !slices.Equal(rbt.ToSlice(), vals[n:]) || rbt.Max != kept {
+				t.FailNow()
+			}
+
+			if !slices.Equal(slices.Collect(rbt.InsertionOrder()), order) {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestStructuralHash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StructuralHash: equal trees", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.StructuralHash() != rbt.Clone().StructuralHash() || NewOrdered[int]().StructuralHash() != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("StructuralHash: different trees", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		hash := rbt.StructuralHash()
+
+		recolored := rbt.Clone()
+		recolored.root.left.isBlack = true
+
+		rotated := rbt.Clone()
+		_ = rotated.RotateLeft(rotated.root)
+
+		changed := rbt.Clone()
+		changed.root.right.right.Val = 101
+
+		if recolored.StructuralHash() == hash || rotated.StructuralHash() == hash || changed.StructuralHash() == hash {
+			t.Fail()
+		}
+	})
+}
+
+func TestIndependentFrom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IndependentFrom: clone", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		clone := rbt.Clone()
+
+		if !clone.EqualTo(rbt) || !clone.IndependentFrom(rbt) || !rbt.IndependentFrom(clone) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IndependentFrom: same tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.IndependentFrom(rbt) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IndependentFrom: shared subtree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		clone := rbt.Clone()
+		clone.root.right.left = rbt.root.right.left
+
+		if clone.IndependentFrom(rbt) || rbt.IndependentFrom(clone) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IndependentFrom: empty trees", func(t *testing.T) {
+		t.Parallel()
+
+		if !NewOrdered[int]().IndependentFrom(initRBTBefore()) || !initRBTBefore().IndependentFrom(NewOrdered[int]()) {
+			t.Fail()
+		}
+	})
+}
+
+func TestBracket(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		val              int
+		lowerOK, upperOK bool
+		lower, upper     int
+	}{
+		{"Bracket: between values", 65, true, true, 60, 70},
+		{"Bracket: exact match", 75, true, true, 75, 75},
+		{"Bracket: below Min", 10, false, true, 0, 20},
+		{"Bracket: above Max", 110, true, false, 100, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			lower, upper, lowerOK, upperOK := initRBTBefore().Bracket(tc.val)
+
+			if lowerOK != tc.lowerOK || upperOK != tc.upperOK || (lower == nil) == lowerOK || (upper == nil) == upperOK {
+				t.FailNow()
+			}
+
+			if (lowerOK && lower.Val != tc.lower) || (upperOK && upper.Val != tc.upper) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Bracket: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if lower, upper, lowerOK, upperOK := NewOrdered[int]().Bracket(1); lower != nil || upper != nil || lowerOK || upperOK {
+			t.Fail()
+		}
+	})
+}
+
+func TestKNearest(t *testing.T) {
+	t.Parallel()
+
+	dist := func(a, b int) int {
+		return max(a-b, b-a)
+	}
+
+	testCases := []struct {
+		name     string
+		center   int
+		k        int
+		expected []int
+	}{
+		{"KNearest: non-positive k", 70, 0, []int{}},
+		{"KNearest: exact match", 70, 3, []int{70, 75, 60}},
+		{"KNearest: tie", 55, 2, []int{50, 60}},
+		{"KNearest: below Min", 0, 2, []int{20, 50}},
+		{"KNearest: above Max", 200, 2, []int{100, 80}},
+		{"KNearest: more than Count", 77, 10, []int{75, 80, 70, 60, 100, 50, 20}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if !slices.Equal(initRBTBefore().KNearest(tc.center, tc.k, dist), tc.expected) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("KNearest: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if nearest := NewOrdered[int]().KNearest(1, 3, dist); nearest == nil || len(nearest) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("KNearest: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 1, 2, 3, 3)
+
+		if nearest := rbt.KNearest(2, 6, dist); !slices.Equal(nearest, []int{2, 1, 1, 1, 3, 3}) {
+			t.Fail()
+		}
+
+		if nearest := rbt.KNearest(3, 3, dist); !slices.Equal(nearest, []int{3, 3, 2}) {
+			t.Fail()
+		}
+	})
+}
+
+func TestEqualValues(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EqualValues: different structure", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+		_ = rbt.InsertAll(100, 80, 75, 70, 60, 50, 20)
+
+		if rbt.EqualTo(initRBTBefore()) || !rbt.EqualValues(initRBTBefore()) || !initRBTBefore().EqualValues(rbt) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualValues: different values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		_, _ = rbt.Delete(20)
+		_, _ = rbt.Insert(10)
+
+		if rbt.EqualValues(initRBTBefore()) || rbt.EqualValues(NewOrdered[int]()) || rbt.EqualValues(nil) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualValues: empty trees", func(t *testing.T) {
+		t.Parallel()
+
+		if !NewOrdered[int]().EqualValues(NewOrdered[int]()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("EqualValues: multisets", func(t *testing.T) {
+		t.Parallel()
+
+		rbt, other := NewMultiset(cmp.Compare[int]), NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 2)
+		other.InsertAll(1, 2, 2)
+
+		if rbt.EqualValues(other) {
+			t.Fail()
+		}
+
+		_, _ = other.Delete(2)
+		_, _ = other.Insert(1)
+
+		if !rbt.EqualValues(other) {
+			t.Fail()
+		}
+	})
+}
+
+func TestCanonicalizeColors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CanonicalizeColors: full tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.CanonicalizeColors()
+
+		if !rbt.IsValid() || slices.ContainsFunc(slices.Collect(rbt.Nodes()), func(rbn *RBNode[int]) bool { return !rbn.isBlack }) {
+			t.Fail()
+		}
+
+		hash := rbt.StructuralHash()
+		rbt.CanonicalizeColors()
+
+		if rbt.StructuralHash() != hash {
+			t.Fail()
+		}
+	})
+
+	t.Run("CanonicalizeColors: different colorings", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		recolored := initRBTBefore()
+		recolored.root.left.isBlack = true
+		recolored.root.left.left.isBlack = false
+		recolored.root.left.right.isBlack = false
+
+		if !recolored.IsValid() || recolored.EqualTo(rbt) {
+			t.FailNow()
+		}
+
+		rbt.CanonicalizeColors()
+		recolored.CanonicalizeColors()
+
+		if !recolored.IsValid() || !recolored.EqualTo(rbt) {
+			t.Fail()
+		}
+	})
+
+	t.Run("CanonicalizeColors: random trees", func(t *testing.T) {
+		t.Parallel()
+
+		for range 100 {
+			rbt := NewOrdered[int]()
+
+			for range rand.IntN(200) {
+				_, _ = rbt.Insert(rand.IntN(1000))
+			}
+
+			rbt.CanonicalizeColors()
+			hash := rbt.StructuralHash()
+			rbt.CanonicalizeColors()
+
+			if !rbt.IsValid() || rbt.StructuralHash() != hash {
+				t.FailNow()
+			}
+		}
+	})
+
+	t.Run("CanonicalizeColors: no valid coloring", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 4 {
+			_, _ = rbt.Insert(i)
+		}
+
+		_ = rbt.RotateLeft(rbt.root)
+		_ = rbt.RotateLeft(rbt.root)
+		hash := rbt.StructuralHash()
+
+		rbt.CanonicalizeColors()
 
-		node, ok := rbt.root.right.right.Next()
-		if ok || node != nil {
+		if rbt.StructuralHash() != hash {
 			t.Fail()
 		}
 	})
 }
 
-func TestPrev(t *testing.T) {
+func TestSuccessorPredecessor(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Prev: root-only tree", func(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		val                        int
+		successorOK, predecessorOK bool
+		successor, predecessor     int
+	}{
+		{"Successor/Predecessor: present value", 70, true, true, 75, 60},
+		{"Successor/Predecessor: absent value", 65, true, true, 70, 60},
+		{"Successor/Predecessor: Min", 20, true, false, 50, 0},
+		{"Successor/Predecessor: Max", 100, false, true, 0, 80},
+		{"Successor/Predecessor: below Min", 10, true, false, 20, 0},
+		{"Successor/Predecessor: above Max", 110, false, true, 0, 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt := initRBTBefore()
+			successor, successorOK := rbt.Successor(tc.val)
+			predecessor, predecessorOK := rbt.Predecessor(tc.val)
+
+			if successorOK != tc.successorOK || predecessorOK != tc.predecessorOK {
+				t.FailNow()
+			}
+
+			if (successorOK && successor.Val != tc.successor) || (predecessorOK && predecessor.Val != tc.predecessor) {
+				t.Fail()
+			}
+
+			if (!successorOK && successor != nil) || (!predecessorOK && predecessor != nil) {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Successor/Predecessor: empty tree", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			root: &RBNode[int]{
-				Val:     20,
-				isBlack: true,
-			},
+		rbt := NewOrdered[int]()
+
+		if _, ok := rbt.Successor(1); ok {
+			t.Fail()
 		}
 
-		node, ok := rbt.root.Prev()
-		if ok || node != nil {
+		if _, ok := rbt.Predecessor(1); ok {
 			t.Fail()
 		}
 	})
+}
 
-	t.Run("Prev: is a left child, has a smaller grandparent", func(t *testing.T) {
+func TestToSlice(t *testing.T) {
+	t.Parallel()
+
+	if !slices.Equal(initRBTBefore().ToSlice(), []int{20, 50, 60, 70, 75, 80, 100}) {
+		t.Fail()
+	}
+
+	if vals := NewOrdered[int]().ToSlice(); vals == nil || len(vals) != 0 {
+		t.Fail()
+	}
+}
+
+func TestColoredSlice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ColoredSlice: full tree", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
+		colored := initRBTBefore().ColoredSlice()
 
-		node, ok := rbt.root.right.left.Prev()
-		if !ok || node != rbt.root {
+		var (
+			vals  []int
+			black []bool
+		)
+
+		for _, c := range colored {
+			vals = append(vals, c.Val)
+			black = append(black, c.Black)
+		}
+
+		if !slices.Equal(vals, []int{20, 50, 60, 70, 75, 80, 100}) || !slices.Equal(black, []bool{true, false, true, true, true, false, true}) {
 			t.Fail()
 		}
 	})
 
-	t.Run("Prev: is a left child", func(t *testing.T) {
+	t.Run("ColoredSlice: empty tree", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := initRBTBefore()
-
-		node, ok := rbt.root.left.left.Prev()
-		if ok || node != nil {
+		if colored := NewOrdered[int]().ColoredSlice(); colored == nil || len(colored) != 0 {
 			t.Fail()
 		}
 	})
 }
 
-func TestFind(t *testing.T) {
+func TestIsBlack(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Find: empty tree", func(t *testing.T) {
+	rbt := initRBTBefore()
+
+	if !rbt.root.IsBlack() || rbt.root.left.IsBlack() || !rbt.root.left.left.IsBlack() {
+		t.Fail()
+	}
+}
+
+func TestSelectRank(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Select: all positions", func(t *testing.T) {
 		t.Parallel()
 
-		node, ok := (&RBTree[int]{}).Find(10)
-		if ok || node != nil {
+		rbt := initRBTBefore()
+
+		for k, val := range []int{20, 50, 60, 70, 75, 80, 100} {
+			if rbn, ok := rbt.Select(k); !ok || rbn.Val != val {
+				t.Fail()
+			}
+		}
+
+		if _, ok := rbt.Select(-1); ok {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Select(7); ok {
 			t.Fail()
 		}
 	})
 
-	t.Run("Find: non-existent value", func(t *testing.T) {
+	testCases := []struct {
+		name  string
+		val   int
+		rank  int
+		found bool
+	}{
+		{"Rank: Min", 20, 0, true},
+		{"Rank: root", 70, 3, true},
+		{"Rank: Max", 100, 6, true},
+		{"Rank: below Min", 10, 0, false},
+		{"Rank: absent value", 65, 3, false},
+		{"Rank: above Max", 110, 7, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if rank, found := initRBTBefore().Rank(tc.val); rank != tc.rank || found != tc.found {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Select, Rank: random inserts and deletes", func(t *testing.T) {
 		t.Parallel()
 
-		rbt := &RBTree[int]{
-			root: &RBNode[int]{
-				Val:     20,
-				isBlack: true,
-			},
-			cmp: cmp.Compare[int],
+		rbt := NewOrdered[int]()
+
+		for range 1000 {
+			_, _ = rbt.Insert(rand.IntN(500))
 		}
 
-		node, ok := rbt.Find(10)
-		if ok || node != nil {
-			t.Fail()
+		for range 300 {
+			_, _ = rbt.Delete(rand.IntN(500))
 		}
-	})
-}
 
-func TestInsert(t *testing.T) {
-	t.Parallel()
+		if !rbt.IsValid() || rbt.root.Size() != rbt.Count {
+			t.FailNow()
+		}
 
-	t.Run("Insert: existent value", func(t *testing.T) {
-		t.Parallel()
+		k := 0
 
-		const val = 75
+		for rbn := range rbt.Nodes() {
+			selected, ok := rbt.Select(k)
+			rank, found := rbt.Rank(rbn.Val)
+
+			if !ok || selected != rbn || !found || rank != k {
+				t.FailNow()
+			}
+
+			k++
+		}
+	})
+
+	t.Run("Select, Rank: stale size", func(t *testing.T) {
+		t.Parallel()
 
 		rbt := initRBTBefore()
+		rbt.root.left.size++
 
-		existentNode, ok := rbt.Insert(val)
-		if ok || !rbt.IsValid() || existentNode == nil || rbt.cmp(existentNode.Val, val) != 0 {
+		if rbt.IsValid() || len(rbt.ValidateVerbose()) != 1 {
 			t.Fail()
 		}
 	})
 }
 
-func TestDelete(t *testing.T) {
+func TestSize(t *testing.T) {
 	t.Parallel()
 
-	t.Run("Delete: the last node", func(t *testing.T) {
+	rbt := initRBTBefore()
+
+	if rbt.root.Size() != 7 || rbt.root.left.Size() != 3 || rbt.Min.Size() != 1 || rbt.root.left.left.left.Size() != 0 {
+		t.Fail()
+	}
+
+	_, _ = rbt.Insert(65)
+
+	if rbt.root.Size() != 8 || rbt.root.left.Size() != 4 {
+		t.Fail()
+	}
+}
+
+func TestCountBetween(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		lo, hi   int
+		expected int
+	}{
+		{"CountBetween: both bounds present", 50, 80, 3},
+		{"CountBetween: lo present", 50, 77, 3},
+		{"CountBetween: hi present", 55, 80, 3},
+		{"CountBetween: both bounds absent", 55, 77, 3},
+		{"CountBetween: adjacent values", 70, 75, 0},
+		{"CountBetween: whole tree", 0, 200, 7},
+		{"CountBetween: equal bounds", 70, 70, 0},
+		{"CountBetween: lo > hi", 80, 50, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if initRBTBefore().CountBetween(tc.lo, tc.hi) != tc.expected {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("At: out of range", func(t *testing.T) {
 		t.Parallel()
 
-		const val = 50
+		rbt := initRBTBefore()
 
-		rbtBefore := &RBTree[int]{
-			root: &RBNode[int]{
-				Val:     val,
-				isBlack: true,
-			},
-			cmp:   cmp.Compare[int],
-			Count: 1,
+		for _, i := range []int{-1, 7, 100} {
+			if _, ok := rbt.At(i); ok {
+				t.Fail()
+			}
 		}
 
-		rbtBefore.Max = rbtBefore.root
-		rbtBefore.Min = rbtBefore.root
-
-		_, ok := rbtBefore.Delete(val)
-		if !ok || !rbtBefore.IsValid() {
+		if _, ok := NewOrdered[int]().At(0); ok {
 			t.Fail()
 		}
 	})
 
-	t.Run("Delete: empty tree", func(t *testing.T) {
+	t.Run("At: matches the sorted slice", func(t *testing.T) {
 		t.Parallel()
 
-		rbtBefore := &RBTree[int]{
-			cmp: cmp.Compare[int],
+		rbt := NewOrdered[int]()
+
+		for range 1000 {
+			_, _ = rbt.Insert(rand.IntN(2000))
 		}
 
-		_, ok := rbtBefore.Delete(10)
-		if ok || !rbtBefore.IsValid() {
+		for range 500 {
+			_, _ = rbt.Delete(rand.IntN(2000))
+		}
+
+		sorted := slices.Collect(rbt.All())
+
+		for i, expected := range sorted {
+			if val, ok := rbt.At(i); !ok || val != expected {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		corrupt  func(*RBTree[int])
+		expected string
+	}{
+		{"Validate: red node with red parent", func(rbt *RBTree[int]) {
+			rbt.root.left.left.isBlack = false
+		}, "red node 20 has red parent 50"},
+		{"Validate: black height mismatch", func(rbt *RBTree[int]) {
+			rbt.root.left.isBlack = true
+		}, "leaf 75 with parent 80 has black height 2, but the first leaf has 3"},
+		{"Validate: order", func(rbt *RBTree[int]) {
+			rbt.root.left.left.Val = 55
+		}, "left child 55 of node 50 is not smaller"},
+		{"Validate: red root", func(rbt *RBTree[int]) {
+			rbt.root.isBlack = false
+		}, "root 70 is red"},
+		{"Validate: Count", func(rbt *RBTree[int]) {
+			rbt.Count++
+		}, "Count is 8, but the tree has 7 nodes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt := initRBTBefore()
+			tc.corrupt(rbt)
+
+			err := rbt.Validate()
+			if !errors.Is(err, ErrInvalidTree) || err.Error() != "rbtree: invalid red-black tree: "+tc.expected || rbt.IsValid() {
+				t.Fail()
+			}
+		})
+	}
+
+	t.Run("Validate: valid trees", func(t *testing.T) {
+		t.Parallel()
+
+		if initRBTBefore().Validate() != nil || NewOrdered[int]().Validate() != nil {
 			t.Fail()
 		}
 	})
 
-	t.Run("Delete: non-existent value", func(t *testing.T) {
+	t.Run("Validate: degenerate tree", func(t *testing.T) {
 		t.Parallel()
 
-		rbtBefore := &RBTree[int]{
-			root: &RBNode[int]{
-				Val:     20,
-				isBlack: true,
-			},
-			cmp:   cmp.Compare[int],
-			Count: 1,
+		const depth = 1000000
+
+		rbt := NewOrdered[int]()
+		rbt.root = &RBNode[int]{isBlack: true, size: depth}
+		rbt.Min = rbt.root
+
+		for rbn, i := rbt.root, 1; i < depth; rbn, i = rbn.right, i+1 {
+			rbn.right = &RBNode[int]{Val: i, parent: rbn, isBlack: true, size: depth - i}
+			rbt.Max = rbn.right
 		}
 
-		rbtBefore.Min = rbtBefore.root
-		rbtBefore.Max = rbtBefore.root
+		rbt.Count = depth
+		expected := fmt.Sprintf("node %d with parent %d has black height %d on the left and %d on the right", depth-2, depth-3, depth-1, depth)
 
-		_, ok := rbtBefore.Delete(30)
-		if ok || !rbtBefore.IsValid() {
+		if err := rbt.Validate(); !errors.Is(err, ErrInvalidTree) || err.Error() != "rbtree: invalid red-black tree: "+expected {
 			t.Fail()
 		}
 	})
@@ -734,3 +3791,131 @@ func BenchmarkRW(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkRWValidated is the InsertDelete part of BenchmarkRW, which additionally checks
+// that Min and Max point to the leftmost and the rightmost nodes every extremesCheckPeriod operations.
+func BenchmarkRWValidated(b *testing.B) {
+	const extremesCheckPeriod = 1024
+
+	treeSizes := map[string]int{
+		"1000":     1000,
+		"100000":   100000,
+		"10000000": 10000000,
+	}
+
+	checkExtremes := func(b *testing.B, rbt *RBTree[int]) {
+		b.Helper()
+
+		if rbt.root == nil {
+			if rbt.Min != nil || rbt.Max != nil {
+				b.Fatal("Min or Max is set in an empty tree")
+			}
+
+			return
+		}
+
+		if rbt.Min != rbt.root.leftmost() || rbt.Max != rbt.root.rightmost() {
+			b.Fatalf("Min %v or Max %v does not match the extremes of the tree", rbt.Min.Val, rbt.Max.Val)
+		}
+	}
+
+	for name, treeSize := range treeSizes {
+		rbt := NewOrdered[int]()
+
+		b.Run("InsertDelete-"+name, func(b *testing.B) {
+			for range b.N {
+				for i := range treeSize {
+					_, _ = rbt.Insert(i)
+
+					if i%extremesCheckPeriod == 0 {
+						checkExtremes(b, rbt)
+					}
+				}
+
+				for i := range treeSize {
+					_, _ = rbt.Delete(i)
+
+					if i%extremesCheckPeriod == 0 {
+						checkExtremes(b, rbt)
+					}
+				}
+
+				checkExtremes(b, rbt)
+			}
+		})
+	}
+}
+
+// recursiveClone is the recursive implementation of RBNode.clone, which BenchmarkClone compares against.
+func recursiveClone[T any](rbn *RBNode[T]) *RBNode[T] {
+	newNode := &RBNode[T]{
+		Val:     rbn.Val,
+		isBlack: rbn.isBlack,
+		size:    rbn.size,
+	}
+
+	if rbn.left != nil {
+		newNode.left = recursiveClone(rbn.left)
+		newNode.left.parent = newNode
+	}
+
+	if rbn.right != nil {
+		newNode.right = recursiveClone(rbn.right)
+		newNode.right.parent = newNode
+	}
+
+	return newNode
+}
+
+func BenchmarkClone(b *testing.B) {
+	const treeSize = 1000000
+
+	rbt := NewOrdered[int]()
+
+	for i := range treeSize {
+		_, _ = rbt.Insert(i)
+	}
+
+	b.Run("recursive", func(b *testing.B) {
+		for range b.N {
+			_ = recursiveClone(rbt.root)
+		}
+	})
+
+	b.Run("iterative", func(b *testing.B) {
+		for range b.N {
+			_ = rbt.root.clone()
+		}
+	})
+
+	b.Run("Clone", func(b *testing.B) {
+		for range b.N {
+			if clone := rbt.Clone(); clone.Count != treeSize {
+				b.Fatal("clone has a wrong number of nodes")
+			}
+		}
+	})
+}
+
+func BenchmarkNewPooled(b *testing.B) {
+	for name, newTree := range map[string]func() *RBTree[int]{
+		"New":       func() *RBTree[int] { return NewOrdered[int]() },
+		"NewPooled": func() *RBTree[int] { return NewPooled(cmp.Compare[int]) },
+	} {
+		rbt := newTree()
+
+		b.Run("InsertDelete-"+name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				for i := range 1000 {
+					_, _ = rbt.Insert(i)
+				}
+
+				for i := range 1000 {
+					_, _ = rbt.Delete(i)
+				}
+			}
+		})
+	}
+}
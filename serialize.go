@@ -0,0 +1,233 @@
+package rbtree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+)
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+
+	return n, err
+}
+
+// WriteToFunc writes the values of the tree to w in ascending order, similarly to [io.WriterTo].
+// Every value is encoded with encode and prefixed with the length of its encoding as a uvarint.
+// In a multiset (see NewMultiset), the value is written once per occurrence.
+// The values are streamed one by one, so the memory usage does not depend on the size of the tree.
+// WriteToFunc returns the number of bytes written and stops at the first write error.
+func (rbt *RBTree[T]) WriteToFunc(w io.Writer, encode func(T) []byte) (int64, error) {
+	var (
+		written int64
+		prefix  [binary.MaxVarintLen64]byte
+	)
+
+	for rbn, ok := rbt.Min, rbt.Min != nil; ok; rbn, ok = rbn.Next() {
+		data := encode(rbn.Val)
+
+		for range rbn.Multiplicity() {
+			n, err := w.Write(prefix[:binary.PutUvarint(prefix[:], uint64(len(data)))])
+			written += int64(n)
+
+			if err != nil {
+				return written, err
+			}
+
+			n, err = w.Write(data)
+			written += int64(n)
+
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// ReadFromFunc replaces the values of the tree with the values read from r until EOF, similarly to [io.ReaderFrom].
+// r must contain length-prefixed encodings of values in strictly ascending order, as written by WriteToFunc.
+// In a multiset (see NewMultiset), equal consecutive values are read as occurrences of the same value.
+// Every encoding is decoded with decode. The slice passed to decode is reused, so decode must not retain it.
+// The buffer grows as the encoding is read, so a corrupted length prefix can't allocate more memory than r provides.
+// Since the values are sorted, each of them is appended next to the Max node and the tree is rebuilt in O(n) time
+// without buffering the values.
+//
+// ReadFromFunc returns the number of bytes read and an error, if any:
+// [ErrNotSorted] if the values are not in ascending order, [io.ErrUnexpectedEOF] if the stream is truncated,
+// [ErrCorrupted] if a length prefix exceeds [math.MaxInt64],
+// or the error returned by r or decode, or [ErrFrozen] if the tree is frozen.
+// In case of an error the tree is left unchanged.
+func (rbt *RBTree[T]) ReadFromFunc(r io.Reader, decode func([]byte) (T, error)) (int64, error) {
+	if rbt.frozen {
+		return 0, ErrFrozen
+	}
+
+	var (
+		tree = rbt.emptyCopy()
+		cr   = &countingReader{r: r}
+		br   = bufio.NewReader(cr)
+		buf  bytes.Buffer
+	)
+
+	tree.deferUpdates = true
+
+	for {
+		size, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return cr.n, err
+		}
+
+		if size > math.MaxInt64 {
+			return cr.n, ErrCorrupted
+		}
+
+		buf.Reset()
+
+		if _, err = io.CopyN(&buf, br, int64(size)); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return cr.n, err
+		}
+
+		val, err := decode(buf.Bytes())
+		if err != nil {
+			return cr.n, err
+		}
+
+		if tree.Max != nil {
+			result := rbt.cmp(val, tree.Max.Val)
+
+			if result == 0 && tree.multiset {
+				tree.Max.dups++
+				tree.Count++
+
+				continue
+			}
+
+			if result <= 0 {
+				return cr.n, ErrNotSorted
+			}
+		}
+
+		tree.appendMax(val)
+	}
+
+	tree.deferUpdates = false
+	tree.updateSubtree(tree.root)
+
+	*rbt = *tree
+
+	return cr.n, nil
+}
+
+// MarshalJSON encodes the values of the tree as a JSON array in ascending order.
+// Every value is encoded with [json.Marshal].
+func (rbt *RBTree[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rbt.ToSlice())
+}
+
+// UnmarshalJSON replaces the values of the tree with the values of a JSON array.
+// The tree must be created with New or NewOrdered beforehand, since the comparison function can't be decoded.
+// If the values are in strictly ascending order (e.g. encoded by MarshalJSON), the tree is rebuilt in O(n) time,
+// otherwise the values are inserted one by one and duplicates are skipped.
+// UnmarshalJSON returns [ErrNoCmp] if the tree has no comparison function, [ErrFrozen] if the tree is frozen,
+// or the decoding error. In case of an error the tree is left unchanged.
+func (rbt *RBTree[T]) UnmarshalJSON(data []byte) error {
+	if rbt.cmp == nil {
+		return ErrNoCmp
+	}
+
+	if rbt.frozen {
+		return ErrFrozen
+	}
+
+	var vals []T
+
+	if err := json.Unmarshal(data, &vals); err != nil {
+		return err
+	}
+
+	rbt.replaceValues(vals)
+
+	return nil
+}
+
+// gobTree is the gob encoding of a tree.
+type gobTree[T any] struct {
+	Count int
+	Vals  []T
+}
+
+// GobEncode encodes the number of values of the tree and the values in ascending order with [encoding/gob].
+func (rbt *RBTree[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(gobTree[T]{Count: rbt.Count, Vals: rbt.ToSlice()}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the values of the tree with the values encoded by GobEncode.
+// The tree must be created with New or NewOrdered beforehand, since the comparison function can't be decoded.
+// The values are rebuilt like in UnmarshalJSON.
+// GobDecode returns [ErrNoCmp] if the tree has no comparison function, [ErrFrozen] if the tree is frozen,
+// [ErrCorrupted] if the number of values does not match the encoded count, or the decoding error.
+// In case of an error the tree is left unchanged.
+func (rbt *RBTree[T]) GobDecode(data []byte) error {
+	if rbt.cmp == nil {
+		return ErrNoCmp
+	}
+
+	if rbt.frozen {
+		return ErrFrozen
+	}
+
+	var decoded gobTree[T]
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+
+	if decoded.Count != len(decoded.Vals) {
+		return ErrCorrupted
+	}
+
+	rbt.replaceValues(decoded.Vals)
+
+	return nil
+}
+
+// replaceValues replaces the values of the tree with vals. If they are in strictly ascending order,
+// the tree is rebuilt in O(n) time, otherwise they are inserted one by one and duplicates are skipped.
+func (rbt *RBTree[T]) replaceValues(vals []T) {
+	if err := rbt.ResetFromSorted(vals); err == nil {
+		return
+	}
+
+	tree := rbt.emptyCopy()
+	tree.InsertAll(vals...)
+
+	*rbt = *tree
+}
@@ -0,0 +1,362 @@
+package rbtree
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+)
+
+func encodeInt(val int) []byte {
+	return binary.AppendVarint(nil, int64(val))
+}
+
+type failingWriter struct {
+	limit int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > fw.limit {
+		n := fw.limit
+		fw.limit = 0
+
+		return n, errWriteFailed
+	}
+
+	fw.limit -= len(p)
+
+	return len(p), nil
+}
+
+func TestWriteToFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WriteToFunc: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		n, err := NewOrdered[int]().WriteToFunc(&buf, encodeInt)
+		if err != nil || n != 0 || buf.Len() != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("WriteToFunc: non-empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		n, err := initRBTBefore().WriteToFunc(&buf, encodeInt)
+		if err != nil || n != int64(buf.Len()) {
+			t.Fail()
+		}
+
+		var expected []byte
+
+		for _, val := range []int{20, 50, 60, 70, 75, 80, 100} {
+			data := encodeInt(val)
+			expected = binary.AppendUvarint(expected, uint64(len(data)))
+			expected = append(expected, data...)
+		}
+
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Fail()
+		}
+	})
+
+	t.Run("WriteToFunc: write error", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := initRBTBefore().WriteToFunc(&failingWriter{limit: 5}, encodeInt)
+		if !errors.Is(err, errWriteFailed) || n != 5 {
+			t.Fail()
+		}
+	})
+}
+
+func decodeInt(data []byte) (int, error) {
+	val, n := binary.Varint(data)
+	if n != len(data) {
+		return 0, errDecodeFailed
+	}
+
+	return int(val), nil
+}
+
+var errDecodeFailed = errors.New("decode failed")
+
+func TestReadFromFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ReadFromFunc: round trip", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		rbt := NewOrdered[int]()
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
+		}
+
+		written, err := rbt.WriteToFunc(&buf, encodeInt)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rbtRead := NewOrdered[int]()
+		_, _ = rbtRead.Insert(-1)
+
+		read, err := rbtRead.ReadFromFunc(&buf, decodeInt)
+		if err != nil || read != written || !rbtRead.IsValid() || rbtRead.Count != rbt.Count {
+			t.FailNow()
+		}
+
+		for node, ok := rbtRead.Min, true; ok; node, ok = node.Next() {
+			if _, found := rbt.Find(node.Val); !found {
+				t.Fail()
+			}
+		}
+	})
+
+	t.Run("ReadFromFunc: multiset round trip", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		rbt := NewMultiset(cmp.Compare[int])
+		rbt.InsertAll(1, 1, 2, 3, 3, 3)
+
+		written, err := rbt.WriteToFunc(&buf, encodeInt)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rbtRead := NewMultiset(cmp.Compare[int])
+
+		read, err := rbtRead.ReadFromFunc(&buf, decodeInt)
+		if err != nil || read != written || !rbtRead.IsValid() || !rbtRead.EqualValues(rbt) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ReadFromFunc: empty stream", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		read, err := rbt.ReadFromFunc(bytes.NewReader(nil), decodeInt)
+		if err != nil || read != 0 || rbt.Count != 0 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("ReadFromFunc: unsorted values", func(t *testing.T) {
+		t.Parallel()
+
+		var data []byte
+
+		for _, val := range []int{1, 3, 2} {
+			data = binary.AppendUvarint(data, uint64(len(encodeInt(val))))
+			data = append(data, encodeInt(val)...)
+		}
+
+		rbt := initRBTBefore()
+
+		if _, err := rbt.ReadFromFunc(bytes.NewReader(data), decodeInt); !errors.Is(err, ErrNotSorted) {
+			t.Fail()
+		}
+
+		if !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ReadFromFunc: truncated stream", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		_, _ = initRBTBefore().WriteToFunc(&buf, encodeInt)
+
+		rbt := NewOrdered[int]()
+
+		_, err := rbt.ReadFromFunc(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), decodeInt)
+		if !errors.Is(err, io.ErrUnexpectedEOF) || rbt.Count != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("ReadFromFunc: truncated length prefix", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		_, err := rbt.ReadFromFunc(bytes.NewReader([]byte{0x80}), decodeInt)
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ReadFromFunc: oversized length prefix", func(t *testing.T) {
+		t.Parallel()
+
+		data := binary.AppendUvarint(nil, math.MaxUint64)
+
+		if _, err := NewOrdered[int]().ReadFromFunc(bytes.NewReader(data), decodeInt); !errors.Is(err, ErrCorrupted) {
+			t.Fail()
+		}
+
+		data = append(binary.AppendUvarint(nil, 1<<30), 1, 2, 3)
+
+		if _, err := NewOrdered[int]().ReadFromFunc(bytes.NewReader(data), decodeInt); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fail()
+		}
+	})
+
+	t.Run("ReadFromFunc: decode error", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte{2, 0x80, 0x80}
+
+		if _, err := NewOrdered[int]().ReadFromFunc(bytes.NewReader(data), decodeInt); !errors.Is(err, errDecodeFailed) {
+			t.Fail()
+		}
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("JSON: round trip", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(initRBTBefore())
+		if err != nil || string(data) != "[20,50,60,70,75,80,100]" {
+			t.FailNow()
+		}
+
+		rbt := NewOrdered[int]()
+		_, _ = rbt.Insert(1)
+
+		if err := json.Unmarshal(data, rbt); err != nil || !rbt.IsValid() || !rbt.EqualValues(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("JSON: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(NewOrdered[int]())
+		if err != nil || string(data) != "[]" {
+			t.Fail()
+		}
+	})
+
+	t.Run("JSON: unsorted values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[string]()
+
+		if err := json.Unmarshal([]byte(`["b","a","c","a"]`), rbt); err != nil || !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), []string{"a", "b", "c"}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("JSON: errors", func(t *testing.T) {
+		t.Parallel()
+
+		if err := json.Unmarshal([]byte("[1]"), &RBTree[int]{}); !errors.Is(err, ErrNoCmp) {
+			t.Fail()
+		}
+
+		rbt := initRBTBefore()
+
+		if err := json.Unmarshal([]byte(`["a"]`), rbt); err == nil || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+
+		rbt.Freeze()
+
+		if err := json.Unmarshal([]byte("[1]"), rbt); !errors.Is(err, ErrFrozen) {
+			t.Fail()
+		}
+	})
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Gob: round trip", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		if err := gob.NewEncoder(&buf).Encode(initRBTBefore()); err != nil {
+			t.FailNow()
+		}
+
+		rbt := NewOrdered[int]()
+		_, _ = rbt.Insert(1)
+
+		if err := gob.NewDecoder(&buf).Decode(rbt); err != nil || !rbt.IsValid() || !rbt.EqualValues(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Gob: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := NewOrdered[int]().GobEncode()
+		if err != nil {
+			t.FailNow()
+		}
+
+		rbt := initRBTBefore()
+
+		if err := rbt.GobDecode(data); err != nil || rbt.Count != 0 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Gob: errors", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := initRBTBefore().GobEncode()
+		if err != nil {
+			t.FailNow()
+		}
+
+		if err := (&RBTree[int]{}).GobDecode(data); !errors.Is(err, ErrNoCmp) {
+			t.Fail()
+		}
+
+		var buf bytes.Buffer
+
+		_ = gob.NewEncoder(&buf).Encode(gobTree[int]{Count: 3, Vals: []int{1, 2}})
+		rbt := initRBTBefore()
+
+		if err := rbt.GobDecode(buf.Bytes()); !errors.Is(err, ErrCorrupted) || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+
+		if err := rbt.GobDecode([]byte("garbage")); err == nil || !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+
+		rbt.Freeze()
+
+		if err := rbt.GobDecode(data); !errors.Is(err, ErrFrozen) {
+			t.Fail()
+		}
+	})
+}
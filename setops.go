@@ -0,0 +1,345 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// mergeHead is the smallest unmerged value of a sorted slice merged by SortedMerge.
+type mergeHead[T cmp.Ordered] struct {
+	val    T
+	source int
+}
+
+// SortedMerge merges slices sorted in ascending order into one sorted slice without duplicates.
+// The heads of the slices are kept in a red-black tree, so merging n values of k slices takes O(n log k) time.
+// The result is allocated once with the capacity equal to the total length of the slices.
+func SortedMerge[T cmp.Ordered](slices ...[]T) []T {
+	heads := New(func(first, second mergeHead[T]) int {
+		if result := cmp.Compare(first.val, second.val); result != 0 {
+			return result
+		}
+
+		return cmp.Compare(first.source, second.source)
+	})
+
+	total := 0
+
+	for i, slice := range slices {
+		if len(slice) > 0 {
+			_, _ = heads.Insert(mergeHead[T]{val: slice[0], source: i})
+		}
+
+		total += len(slice)
+	}
+
+	positions := make([]int, len(slices))
+	result := make([]T, 0, total)
+
+	for heads.Count > 0 {
+		head, _ := heads.Delete(heads.Min.Val)
+
+		if len(result) == 0 || cmp.Compare(result[len(result)-1], head.val) != 0 {
+			result = append(result, head.val)
+		}
+
+		positions[head.source]++
+
+		if slice := slices[head.source]; positions[head.source] < len(slice) {
+			_, _ = heads.Insert(mergeHead[T]{val: slice[positions[head.source]], source: head.source})
+		}
+	}
+
+	return result
+}
+
+// Subtract deletes every value present in other from the tree in place.
+// In a multiset (see NewMultiset), all occurrences of such values are deleted.
+// Both trees are walked in order simultaneously and the common nodes are deleted as they are found without searching,
+// so Subtract takes O(m + n + k log n) time for k deleted values, the logarithm coming from the updates
+// of the augmented data. Subtract panics if the tree is frozen.
+func (rbt *RBTree[T]) Subtract(other *RBTree[T]) {
+	rbt.checkMutable()
+
+	if rbt == other {
+		rbt.Clear()
+
+		return
+	}
+
+	rbt.deleteAgainst(other, true)
+}
+
+// Retain deletes every value absent from other from the tree in place, keeping only the common values.
+// In a multiset (see NewMultiset), all occurrences of such values are deleted.
+// Like in Subtract, the nodes are deleted during the walk, so Retain takes O(m + n + k log n) time for k deleted values.
+// Retain panics if the tree is frozen.
+func (rbt *RBTree[T]) Retain(other *RBTree[T]) {
+	rbt.checkMutable()
+
+	if rbt == other {
+		return
+	}
+
+	rbt.deleteAgainst(other, false)
+}
+
+// Union returns a new tree with the values of both trees, ordered by the comparison function of the tree,
+// with the same options. Of equal values, the value of the tree is kept. The sorted sequences of the trees are merged
+// and the result is built from the merged values, which takes O(m + n) time. The trees are not modified.
+// If the tree is a multiset (see NewMultiset), a value occurs in the result as many times as in the tree where it occurs more.
+func (rbt *RBTree[T]) Union(other *RBTree[T]) *RBTree[T] {
+	var (
+		vals = make([]T, 0, rbt.Count+other.Count)
+		dups []int
+	)
+
+	rbn, otherRBN := rbt.Min, other.Min
+
+	for rbn != nil || otherRBN != nil {
+		var result int
+
+		switch {
+		case otherRBN == nil:
+			result = -1
+		case rbn == nil:
+			result = 1
+		default:
+			result = rbt.cmp(rbn.Val, otherRBN.Val)
+		}
+
+		switch {
+		case result < 0:
+			vals, dups = append(vals, rbn.Val), append(dups, rbn.dups)
+			rbn = rbn.nextNode()
+		case result > 0:
+			vals, dups = append(vals, otherRBN.Val), append(dups, otherRBN.dups)
+			otherRBN = otherRBN.nextNode()
+		default:
+			vals, dups = append(vals, rbn.Val), append(dups, max(rbn.dups, otherRBN.dups))
+			rbn, otherRBN = rbn.nextNode(), otherRBN.nextNode()
+		}
+	}
+
+	return rbt.buildCounted(vals, dups)
+}
+
+// SymmetricDifference returns a new tree with the values present in exactly one of the trees,
+// ordered by the comparison function of the tree, with the same options. The values are collected in a single
+// walk over both trees (see Changes) and the result is built from them, which takes O(m + n) time.
+// The trees are not modified. If the tree is a multiset (see NewMultiset), a value occurs in the result
+// as many times as the numbers of its occurrences in the trees differ.
+func (rbt *RBTree[T]) SymmetricDifference(other *RBTree[T]) *RBTree[T] {
+	var (
+		vals []T
+		dups []int
+	)
+
+	for val := range rbt.Changes(other) {
+		if len(vals) > 0 && rbt.cmp(vals[len(vals)-1], val) == 0 {
+			dups[len(dups)-1]++
+
+			continue
+		}
+
+		vals, dups = append(vals, val), append(dups, 0)
+	}
+
+	return rbt.buildCounted(vals, dups)
+}
+
+// buildCounted returns a new tree with the same options as the tree, which is built from the distinct sorted values.
+// If the new tree is a multiset, every value vals[i] gets dups[i] extra occurrences.
+func (rbt *RBTree[T]) buildCounted(vals []T, dups []int) *RBTree[T] {
+	tree := rbt.emptyCopy()
+	root := buildSorted(vals, nil, 0, redDepth(len(vals)))
+	count := len(vals)
+
+	if tree.multiset && root != nil {
+		i := 0
+
+		for rbn := root.leftmost(); rbn != nil; rbn, i = rbn.nextNode(), i+1 {
+			rbn.dups = dups[i]
+			count += dups[i]
+		}
+	}
+
+	tree.setRoot(root, count)
+
+	return tree
+}
+
+// IsSubsetOf checks if every value of the tree exists in other. Both trees are walked in order simultaneously
+// until the first value missing in other, which takes O(m + n) time. An empty tree is a subset of any tree.
+// In a multiset (see NewMultiset), every value must occur in other at least as many times as in the tree.
+func (rbt *RBTree[T]) IsSubsetOf(other *RBTree[T]) bool {
+	if rbt.Count > other.Count {
+		return false
+	}
+
+	rbn, otherRBN := rbt.Min, other.Min
+
+	for rbn != nil {
+		result := -1
+
+		if otherRBN != nil {
+			result = rbt.cmp(rbn.Val, otherRBN.Val)
+		}
+
+		switch {
+		case result < 0:
+			return false
+		case result > 0:
+			otherRBN = otherRBN.nextNode()
+		default:
+			if rbn.Multiplicity() > otherRBN.Multiplicity() {
+				return false
+			}
+
+			rbn, otherRBN = rbn.nextNode(), otherRBN.nextNode()
+		}
+	}
+
+	return true
+}
+
+// IsSupersetOf checks if every value of other exists in the tree (see IsSubsetOf).
+// Any tree is a superset of an empty tree.
+func (rbt *RBTree[T]) IsSupersetOf(other *RBTree[T]) bool {
+	return other.IsSubsetOf(rbt)
+}
+
+// CommonRange returns the overlap [max(Min, other.Min), min(Max, other.Max)] of the value ranges of the trees
+// and true, or empty values and false if the ranges are disjoint or a tree is empty. CommonRange compares
+// only the extremes of the trees, so it takes O(1) time, which makes it a cheap check before intersecting the trees.
+func (rbt *RBTree[T]) CommonRange(other *RBTree[T]) (lo, hi T, ok bool) {
+	if rbt.Min == nil || other.Min == nil {
+		return lo, hi, false
+	}
+
+	lo, hi = rbt.Min.Val, rbt.Max.Val
+
+	if rbt.cmp(other.Min.Val, lo) > 0 {
+		lo = other.Min.Val
+	}
+
+	if rbt.cmp(other.Max.Val, hi) < 0 {
+		hi = other.Max.Val
+	}
+
+	if rbt.cmp(lo, hi) > 0 {
+		var zero T
+
+		return zero, zero, false
+	}
+
+	return lo, hi, true
+}
+
+// ChangeKind tells how a value differs between two trees compared by Changes.
+type ChangeKind int
+
+const (
+	// Added marks a value present only in the new tree.
+	Added ChangeKind = iota
+	// Removed marks a value present only in the old tree.
+	Removed
+)
+
+// Changes returns an iterator over the values, which differ between the tree (new) and old, in ascending order.
+// Every value is tagged with Added if it is present only in the tree, or Removed if it is present only in old,
+// so the yielded changes transform old into the tree. Both trees are walked in order simultaneously,
+// which takes O(m + n) time. Neither tree is modified, but they must not be modified during the iteration.
+// In multisets (see NewMultiset), a value is yielded once for every occurrence, by which the trees differ.
+func (rbt *RBTree[T]) Changes(old *RBTree[T]) iter.Seq2[T, ChangeKind] {
+	return func(yield func(T, ChangeKind) bool) {
+		rbn, oldRBN := rbt.Min, old.Min
+
+		for rbn != nil || oldRBN != nil {
+			var result int
+
+			switch {
+			case oldRBN == nil:
+				result = -1
+			case rbn == nil:
+				result = 1
+			default:
+				result = rbt.cmp(rbn.Val, oldRBN.Val)
+			}
+
+			switch {
+			case result < 0:
+				if !yieldChanges(yield, rbn.Val, Added, rbn.Multiplicity()) {
+					return
+				}
+
+				rbn = rbn.nextNode()
+			case result > 0:
+				if !yieldChanges(yield, oldRBN.Val, Removed, oldRBN.Multiplicity()) {
+					return
+				}
+
+				oldRBN = oldRBN.nextNode()
+			default:
+				diff := rbn.Multiplicity() - oldRBN.Multiplicity()
+
+				if diff > 0 && !yieldChanges(yield, rbn.Val, Added, diff) ||
+					diff < 0 && !yieldChanges(yield, rbn.Val, Removed, -diff) {
+					return
+				}
+
+				rbn, oldRBN = rbn.nextNode(), oldRBN.nextNode()
+			}
+		}
+	}
+}
+
+// yieldChanges yields the value with the kind times times and returns false if the iteration is stopped.
+func yieldChanges[T any](yield func(T, ChangeKind) bool, val T, kind ChangeKind, times int) bool {
+	for range times {
+		if !yield(val, kind) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// deleteOccurrences deletes the node of the tree with all occurrences of its value and returns the node
+// of the next value, if any.
+func (rbt *RBTree[T]) deleteOccurrences(rbn *RBNode[T]) *RBNode[T] {
+	next := rbn.nextNode()
+
+	rbt.Count -= rbn.dups
+	rbn.dups = 0
+	rbt.deleteNode(rbn)
+
+	return next
+}
+
+// deleteAgainst walks the tree and other in order simultaneously and deletes the nodes of the tree,
+// whose values are present in other if found is true, or absent from other otherwise.
+func (rbt *RBTree[T]) deleteAgainst(other *RBTree[T], found bool) {
+	rbn, otherRBN := rbt.Min, other.Min
+
+	for rbn != nil {
+		result := -1
+
+		if otherRBN != nil {
+			result = rbt.cmp(rbn.Val, otherRBN.Val)
+		}
+
+		switch {
+		case result > 0:
+			otherRBN = otherRBN.nextNode()
+		case result == 0 && found:
+			rbn, otherRBN = rbt.deleteOccurrences(rbn), otherRBN.nextNode()
+		case result < 0 && !found:
+			rbn = rbt.deleteOccurrences(rbn)
+		case result == 0:
+			rbn, otherRBN = rbn.nextNode(), otherRBN.nextNode()
+		default:
+			rbn = rbn.nextNode()
+		}
+	}
+}
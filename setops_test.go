@@ -0,0 +1,506 @@
+package rbtree
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSortedMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SortedMerge: no slices", func(t *testing.T) {
+		t.Parallel()
+
+		result := SortedMerge[int]()
+		if result == nil || len(result) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("SortedMerge: empty slices", func(t *testing.T) {
+		t.Parallel()
+
+		if len(SortedMerge([]int{}, nil)) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("SortedMerge: duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		result := SortedMerge([]int{1, 3, 3, 7}, nil, []int{2, 3, 8}, []int{0, 7, 9})
+		if !slices.Equal(result, []int{0, 1, 2, 3, 7, 8, 9}) || cap(result) != 10 {
+			t.Fail()
+		}
+	})
+}
+
+func TestSubtract(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Subtract: empty other", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Subtract(NewOrdered[int]())
+
+		if !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Subtract: common values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		other := NewOrdered[int]()
+
+		for _, val := range []int{10, 20, 70, 90, 100, 110} {
+			_, _ = other.Insert(val)
+		}
+
+		rbt.Subtract(other)
+
+		if !rbt.IsValid() || other.Count != 6 {
+			t.Fail()
+		}
+
+		for _, val := range []int{50, 60, 75, 80} {
+			if _, ok := rbt.Find(val); !ok {
+				t.Fail()
+			}
+		}
+
+		if rbt.Count != 4 || rbt.Min.Val != 50 || rbt.Max.Val != 80 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Subtract: itself", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Subtract(initRBTBefore())
+
+		if rbt.Count != 0 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Subtract: same tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Subtract(rbt)
+
+		if rbt.Count != 0 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Subtract: random multisets", func(t *testing.T) {
+		t.Parallel()
+
+		for range 100 {
+			rbt, other := NewMultiset(cmp.Compare[int], WithInsertionOrder[int]()), NewMultiset(cmp.Compare[int])
+
+			for range rand.IntN(300) {
+				_, _ = rbt.Insert(rand.IntN(200))
+				_, _ = other.Insert(rand.IntN(200))
+			}
+
+			expected := slices.DeleteFunc(rbt.ToSlice(), other.Contains)
+			rbt.Subtract(other)
+
+			if !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), expected) || rbt.Count != len(expected) {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestRetain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Retain: empty other", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Retain(NewOrdered[int]())
+
+		if rbt.Count != 0 || !rbt.IsValid() {
+			t.Fail()
+		}
+	})
+
+	t.Run("Retain: common values", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		other := NewOrdered[int]()
+
+		for _, val := range []int{10, 20, 70, 90, 100, 110} {
+			_, _ = other.Insert(val)
+		}
+
+		rbt.Retain(other)
+
+		if !rbt.IsValid() || rbt.Count != 3 || rbt.Min.Val != 20 || rbt.Max.Val != 100 {
+			t.Fail()
+		}
+
+		if _, ok := rbt.Find(70); !ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("Retain: itself", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Retain(initRBTBefore())
+
+		if !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Retain: same tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.Retain(rbt)
+
+		if !rbt.EqualTo(initRBTBefore()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Retain: random multisets", func(t *testing.T) {
+		t.Parallel()
+
+		for range 100 {
+			rbt, other := NewMultiset(cmp.Compare[int], WithInsertionOrder[int]()), NewMultiset(cmp.Compare[int])
+
+			for range rand.IntN(300) {
+				_, _ = rbt.Insert(rand.IntN(200))
+				_, _ = other.Insert(rand.IntN(200))
+			}
+
+			expected := slices.DeleteFunc(rbt.ToSlice(), func(val int) bool { return !other.Contains(val) })
+			rbt.Retain(other)
+
+			if !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), expected) || rbt.Count != len(expected) {
+				t.FailNow()
+			}
+		}
+	})
+}
+
+func TestSetOpsMultiset(t *testing.T) {
+	t.Parallel()
+
+	build := func(vals ...int) *RBTree[int] {
+		rbt := NewMultiset(cmp.Compare[int])
+
+		for _, val := range vals {
+			_, _ = rbt.Insert(val)
+		}
+
+		return rbt
+	}
+
+	t.Run("Subtract: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := build(1, 1, 2, 3, 3, 3)
+		rbt.Subtract(build(1, 3))
+
+		if !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), []int{2}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Retain: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := build(1, 1, 2, 3, 3)
+		rbt.Retain(build(2))
+
+		if !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), []int{2}) {
+			t.Fail()
+		}
+
+		rbt = build(1, 1, 2, 3, 3)
+		rbt.Retain(build(1, 3))
+
+		if !rbt.IsValid() || !slices.Equal(rbt.ToSlice(), []int{1, 1, 3, 3}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Union: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		union := build(1, 1, 2, 3).Union(build(1, 3, 3, 4))
+
+		if !union.IsValid() || !slices.Equal(union.ToSlice(), []int{1, 1, 2, 3, 3, 4}) || union.Count != 6 {
+			t.Fail()
+		}
+	})
+
+	t.Run("SymmetricDifference: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		diff := build(1, 1, 1, 2, 3).SymmetricDifference(build(1, 3, 3, 3, 4))
+
+		if !diff.IsValid() || !slices.Equal(diff.ToSlice(), []int{1, 1, 2, 3, 3, 4}) || diff.Count != 6 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Changes: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		var changes []string
+
+		for val, kind := range build(1, 1, 1, 2).Changes(build(1, 2, 2, 3)) {
+			changes = append(changes, fmt.Sprint(val, kind == Added))
+		}
+
+		if !slices.Equal(changes, []string{"1 true", "1 true", "2 false", "3 false"}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IsSubsetOf: multiset", func(t *testing.T) {
+		t.Parallel()
+
+		if !build(1, 1, 3).IsSubsetOf(build(1, 1, 1, 2, 3)) || build(1, 1, 3).IsSubsetOf(build(1, 2, 3, 4)) {
+			t.Fail()
+		}
+
+		if !build(1).IsSubsetOf(build(1, 1)) || build(1, 1).IsSubsetOf(build(1, 2, 3)) {
+			t.Fail()
+		}
+	})
+}
+
+func TestChanges(t *testing.T) {
+	t.Parallel()
+
+	type change struct {
+		val  int
+		kind ChangeKind
+	}
+
+	collect := func(seq func(func(int, ChangeKind) bool)) []change {
+		changes := []change{}
+
+		for val, kind := range seq {
+			changes = append(changes, change{val, kind})
+		}
+
+		return changes
+	}
+
+	t.Run("Changes: added and removed", func(t *testing.T) {
+		t.Parallel()
+
+		old := initRBTBefore()
+		rbt := initRBTBefore()
+		_, _ = rbt.Delete(20)
+		_, _ = rbt.Delete(100)
+		_, _ = rbt.Insert(65)
+		_, _ = rbt.Insert(110)
+
+		changes := collect(rbt.Changes(old))
+		expected := []change{{20, Removed}, {65, Added}, {100, Removed}, {110, Added}}
+
+		if !slices.Equal(changes, expected) || old.Count != 7 || rbt.Count != 7 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Changes: equal trees", func(t *testing.T) {
+		t.Parallel()
+
+		if len(collect(initRBTBefore().Changes(initRBTBefore()))) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Changes: empty trees", func(t *testing.T) {
+		t.Parallel()
+
+		added := collect(initRBTBefore().Changes(NewOrdered[int]()))
+		removed := collect(NewOrdered[int]().Changes(initRBTBefore()))
+
+		if len(added) != 7 || len(removed) != 7 || added[0] != (change{20, Added}) || removed[6] != (change{100, Removed}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Changes: early break", func(t *testing.T) {
+		t.Parallel()
+
+		count := 0
+
+		for range initRBTBefore().Changes(NewOrdered[int]()) {
+			count++
+
+			break
+		}
+
+		if count != 1 {
+			t.Fail()
+		}
+	})
+}
+
+func TestCommonRange(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		other  []int
+		lo, hi int
+		ok     bool
+	}{
+		{"CommonRange: overlap", []int{60, 150}, 60, 100, true},
+		{"CommonRange: contained", []int{55, 65}, 55, 65, true},
+		{"CommonRange: touching", []int{100, 200}, 100, 100, true},
+		{"CommonRange: disjoint", []int{101, 200}, 0, 0, false},
+		{"CommonRange: empty tree", []int{}, 0, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			other := NewOrdered[int]()
+
+			for _, val := range tc.other {
+				_, _ = other.Insert(val)
+			}
+
+			lo, hi, ok := initRBTBefore().CommonRange(other)
+			if lo != tc.lo || hi != tc.hi || ok != tc.ok {
+				t.Fail()
+			}
+
+			lo, hi, ok = other.CommonRange(initRBTBefore())
+			if lo != tc.lo || hi != tc.hi || ok != tc.ok {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		vals     []int
+		other    []int
+		expected []int
+	}{
+		{"Union: overlapping trees", []int{1, 3, 5, 7}, []int{2, 3, 4, 7, 9}, []int{1, 2, 3, 4, 5, 7, 9}},
+		{"Union: disjoint trees", []int{1, 2}, []int{3, 4}, []int{1, 2, 3, 4}},
+		{"Union: empty other", []int{1, 2}, []int{}, []int{1, 2}},
+		{"Union: empty trees", []int{}, []int{}, []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt, other := NewOrdered[int](), NewOrdered[int]()
+			_ = rbt.InsertAll(tc.vals...)
+			_ = other.InsertAll(tc.other...)
+
+			union := rbt.Union(other)
+
+			if !union.IsValid() || !slices.Equal(union.ToSlice(), tc.expected) {
+				t.Fail()
+			}
+
+			if !slices.Equal(rbt.ToSlice(), tc.vals) || !slices.Equal(other.ToSlice(), tc.other) || !union.IndependentFrom(rbt) {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		vals     []int
+		other    []int
+		expected []int
+	}{
+		{"SymmetricDifference: overlapping trees", []int{1, 3, 5, 7}, []int{2, 3, 4, 7, 9}, []int{1, 2, 4, 5, 9}},
+		{"SymmetricDifference: equal trees", []int{1, 2}, []int{1, 2}, []int{}},
+		{"SymmetricDifference: empty other", []int{1, 2}, []int{}, []int{1, 2}},
+		{"SymmetricDifference: empty trees", []int{}, []int{}, []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt, other := NewOrdered[int](), NewOrdered[int]()
+			_ = rbt.InsertAll(tc.vals...)
+			_ = other.InsertAll(tc.other...)
+
+			difference := rbt.SymmetricDifference(other)
+
+			if !difference.IsValid() || !slices.Equal(difference.ToSlice(), tc.expected) {
+				t.Fail()
+			}
+
+			if !slices.Equal(rbt.ToSlice(), tc.vals) || !slices.Equal(other.ToSlice(), tc.other) {
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestIsSubsetOf(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		vals     []int
+		other    []int
+		subset   bool
+		superset bool
+	}{
+		{"IsSubsetOf: proper subset", []int{3, 7}, []int{2, 3, 4, 7, 9}, true, false},
+		{"IsSubsetOf: equal trees", []int{1, 2}, []int{1, 2}, true, true},
+		{"IsSubsetOf: partial overlap", []int{1, 2, 3}, []int{2, 3, 4}, false, false},
+		{"IsSubsetOf: superset", []int{1, 2, 3}, []int{2}, false, true},
+		{"IsSubsetOf: empty tree", []int{}, []int{1}, true, false},
+		{"IsSubsetOf: empty trees", []int{}, []int{}, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rbt, other := NewOrdered[int](), NewOrdered[int]()
+			_ = rbt.InsertAll(tc.vals...)
+			_ = other.InsertAll(tc.other...)
+
+			if rbt.IsSubsetOf(other) != tc.subset || rbt.IsSupersetOf(other) != tc.superset {
+				t.Fail()
+			}
+		})
+	}
+}
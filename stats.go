@@ -0,0 +1,113 @@
+package rbtree
+
+// DepthHistogram returns the number of nodes at every depth of the tree: the element d of the result
+// is the number of nodes at the distance d from the root. For an empty tree, an empty slice is returned.
+func (rbt *RBTree[T]) DepthHistogram() []int {
+	histogram := []int{}
+
+	if rbt.root == nil {
+		return histogram
+	}
+
+	for level := []*RBNode[T]{rbt.root}; len(level) > 0; {
+		histogram = append(histogram, len(level))
+		next := make([]*RBNode[T], 0, 2*len(level))
+
+		for _, rbn := range level {
+			if rbn.left != nil {
+				next = append(next, rbn.left)
+			}
+
+			if rbn.right != nil {
+				next = append(next, rbn.right)
+			}
+		}
+
+		level = next
+	}
+
+	return histogram
+}
+
+// TotalPathLength returns the sum of the depths of all nodes of the tree, which is the total cost of searching
+// for every value. Divided by Count, it gives the average depth of a node. For an empty tree, 0 is returned.
+func (rbt *RBTree[T]) TotalPathLength() int {
+	total := 0
+
+	for depth, count := range rbt.DepthHistogram() {
+		total += depth * count
+	}
+
+	return total
+}
+
+// Height returns the number of nodes on the longest path from the root to a leaf, like Describe.
+// For an empty tree, 0 is returned. Height takes O(n) time.
+func (rbt *RBTree[T]) Height() int {
+	return rbt.root.height()
+}
+
+// BlackHeight returns the number of black nodes on a path from the root to a leaf, including the root, like Describe.
+// In a valid tree all such paths contain the same number of black nodes; otherwise the maximum is taken,
+// like in BlackHeightHistogram. For an empty tree, 0 is returned. BlackHeight takes O(n) time.
+func (rbt *RBTree[T]) BlackHeight() int {
+	return rbt.root.blackHeights(nil)
+}
+
+// IsHeightBalanced checks if the heights of the left and the right subtrees of every node differ by no more than maxSkew.
+// Red-black trees do not guarantee such balance (AVL trees do for maxSkew equal to 1),
+// so IsHeightBalanced is a diagnostic of how balanced a particular tree happens to be.
+func (rbt *RBTree[T]) IsHeightBalanced(maxSkew int) bool {
+	_, ok := rbt.root.heightBalanced(maxSkew)
+
+	return ok
+}
+
+// BlackHeightHistogram returns the number of nodes for every black height of their subtrees.
+// The black height of a node is the number of black nodes on a path from the node down to a leaf, not counting the node itself.
+// In a valid tree all such paths contain the same number of black nodes; otherwise the maximum is taken.
+// For an empty tree, an empty map is returned.
+func (rbt *RBTree[T]) BlackHeightHistogram() map[int]int {
+	histogram := make(map[int]int)
+
+	rbt.root.blackHeights(histogram)
+
+	return histogram
+}
+
+// TreeStats is a summary of the shape and the contents of a tree returned by Describe.
+type TreeStats[T any] struct {
+	// Count is the number of values (see RBTree.Count).
+	Count int
+	// Height is the number of nodes on the longest path from the root to a leaf.
+	Height int
+	// BlackHeight is the number of black nodes on a path from the root to a leaf, including the root.
+	BlackHeight int
+	// Red and Black are the numbers of red and black nodes.
+	Red   int
+	Black int
+	// Leaves is the number of nodes without children.
+	Leaves int
+	// Min and Max are the smallest and the biggest values, which are set if HasMin and HasMax are true.
+	Min    T
+	Max    T
+	HasMin bool
+	HasMax bool
+}
+
+// Describe returns the summary of the tree in a single traversal. For an empty tree, all numbers are 0
+// and HasMin and HasMax are false.
+func (rbt *RBTree[T]) Describe() TreeStats[T] {
+	stats := TreeStats[T]{Count: rbt.Count}
+
+	if rbt.root == nil {
+		return stats
+	}
+
+	stats.Min, stats.Max = rbt.Min.Val, rbt.Max.Val
+	stats.HasMin, stats.HasMax = true, true
+	stats.Height = rbt.root.describe(&stats)
+	stats.BlackHeight = rbt.BlackHeight()
+
+	return stats
+}
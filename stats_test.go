@@ -0,0 +1,225 @@
+package rbtree
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestDepthHistogram(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DepthHistogram: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		histogram := NewOrdered[int]().DepthHistogram()
+		if histogram == nil || len(histogram) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("DepthHistogram: full tree", func(t *testing.T) {
+		t.Parallel()
+
+		if !slices.Equal(initRBTBefore().DepthHistogram(), []int{1, 2, 4}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("DepthHistogram: sequential inserts", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
+		}
+
+		histogram := rbt.DepthHistogram()
+		total := 0
+
+		for _, count := range histogram {
+			total += count
+		}
+
+		if total != rbt.Count || len(histogram) > 20 {
+			t.Fail()
+		}
+	})
+}
+
+func TestHeight(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Height: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		if rbt.Height() != 0 || rbt.BlackHeight() != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Height: full tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+
+		if rbt.Height() != 3 || rbt.BlackHeight() != 2 {
+			t.Fail()
+		}
+	})
+
+	t.Run("Height: sequential insertions", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 100 {
+			_, _ = rbt.Insert(i)
+		}
+
+		stats := rbt.Describe()
+
+		if rbt.Height() != stats.Height || rbt.BlackHeight() != stats.BlackHeight || rbt.Height() != len(rbt.DepthHistogram()) {
+			t.Fail()
+		}
+	})
+
+	t.Run("BlackHeight: different paths", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		rbt.root.right.isBlack = true
+
+		if rbt.BlackHeight() != 3 || rbt.BlackHeight() != rbt.Describe().BlackHeight {
+			t.Fail()
+		}
+	})
+}
+
+func TestIsHeightBalanced(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IsHeightBalanced: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if !NewOrdered[int]().IsHeightBalanced(0) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IsHeightBalanced: perfect tree", func(t *testing.T) {
+		t.Parallel()
+
+		if !initRBTBefore().IsHeightBalanced(0) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IsHeightBalanced: skewed tree", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		_, _ = rbt.Insert(10)
+		_, _ = rbt.Insert(5)
+
+		if rbt.IsHeightBalanced(0) || !rbt.IsHeightBalanced(1) {
+			t.Fail()
+		}
+	})
+
+	t.Run("IsHeightBalanced: negative skew", func(t *testing.T) {
+		t.Parallel()
+
+		if initRBTBefore().IsHeightBalanced(-1) {
+			t.Fail()
+		}
+	})
+}
+
+func TestBlackHeightHistogram(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BlackHeightHistogram: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		histogram := NewOrdered[int]().BlackHeightHistogram()
+		if histogram == nil || len(histogram) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("BlackHeightHistogram: full tree", func(t *testing.T) {
+		t.Parallel()
+
+		if !maps.Equal(initRBTBefore().BlackHeightHistogram(), map[int]int{0: 4, 1: 3}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("BlackHeightHistogram: sequential inserts", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := NewOrdered[int]()
+
+		for i := range 1000 {
+			_, _ = rbt.Insert(i)
+		}
+
+		total := 0
+
+		for _, count := range rbt.BlackHeightHistogram() {
+			total += count
+		}
+
+		if total != rbt.Count {
+			t.Fail()
+		}
+	})
+}
+
+func TestTotalPathLength(t *testing.T) {
+	t.Parallel()
+
+	single := NewOrdered[int]()
+	_, _ = single.Insert(1)
+
+	if NewOrdered[int]().TotalPathLength() != 0 || single.TotalPathLength() != 0 || initRBTBefore().TotalPathLength() != 10 {
+		t.Fail()
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Describe: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		if NewOrdered[int]().Describe() != (TreeStats[int]{}) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Describe: full tree", func(t *testing.T) {
+		t.Parallel()
+
+		expected := TreeStats[int]{
+			Count:       7,
+			Height:      3,
+			BlackHeight: 2,
+			Red:         2,
+			Black:       5,
+			Leaves:      4,
+			Min:         20,
+			Max:         100,
+			HasMin:      true,
+			HasMax:      true,
+		}
+
+		if initRBTBefore().Describe() != expected {
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package rbtree
+
+import (
+	"cmp"
+	"sync"
+)
+
+// SyncTree is a red-black tree safe for concurrent use by multiple goroutines.
+// Reading methods hold a read lock, so they run in parallel, while mutating methods hold the write lock.
+// The tree and the lock are not exposed, so the tree can't be accessed without the lock.
+// Values are returned instead of nodes, since a node may be changed by a concurrent mutation.
+type SyncTree[T any] struct {
+	mu   sync.RWMutex
+	tree *RBTree[T]
+}
+
+// NewSyncTree returns an empty thread-safe red-black tree. The arguments are the same as for New.
+func NewSyncTree[T any](cmp func(T, T) int, opts ...Option[T]) *SyncTree[T] {
+	return &SyncTree[T]{
+		tree: New(cmp, opts...),
+	}
+}
+
+// NewOrderedSyncTree returns an empty thread-safe red-black tree for primitive types ([cmp.Ordered]).
+func NewOrderedSyncTree[T cmp.Ordered](opts ...Option[T]) *SyncTree[T] {
+	return NewSyncTree(cmp.Compare[T], opts...)
+}
+
+// Insert adds a new value to the tree and returns true if the value did not exist.
+func (st *SyncTree[T]) Insert(val T) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	_, ok := st.tree.Insert(val)
+
+	return ok
+}
+
+// Delete deletes the value from the tree.
+// Delete returns the deleted value and true if the value existed. It returns an empty value and false otherwise.
+func (st *SyncTree[T]) Delete(val T) (T, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.Delete(val)
+}
+
+// Find returns the stored value equal to val and true if it exists in the tree.
+func (st *SyncTree[T]) Find(val T) (T, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	rbn, ok := st.tree.Find(val)
+	if !ok {
+		var found T
+
+		return found, false
+	}
+
+	return rbn.Val, true
+}
+
+// Contains checks if the value exists in the tree.
+func (st *SyncTree[T]) Contains(val T) bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Contains(val)
+}
+
+// Len returns the number of values in the tree.
+func (st *SyncTree[T]) Len() int {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Len()
+}
+
+// Snapshot returns a copy of the tree made with Clone under the read lock.
+// The copy is an ordinary tree, which is not synchronized and is not affected by later mutations of the SyncTree.
+func (st *SyncTree[T]) Snapshot() *RBTree[T] {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Clone()
+}
@@ -0,0 +1,80 @@
+package rbtree
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SyncTree: single goroutine", func(t *testing.T) {
+		t.Parallel()
+
+		st := NewOrderedSyncTree[int]()
+
+		if !st.Insert(1) || st.Insert(1) || !st.Contains(1) || st.Len() != 1 {
+			t.Fail()
+		}
+
+		if val, ok := st.Find(1); !ok || val != 1 {
+			t.Fail()
+		}
+
+		if val, ok := st.Delete(1); !ok || val != 1 || st.Contains(1) {
+			t.Fail()
+		}
+
+		if _, ok := st.Find(1); ok {
+			t.Fail()
+		}
+	})
+
+	t.Run("SyncTree: concurrent readers and writers", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			writers      = 8
+			perGoroutine = 500
+		)
+
+		st := NewOrderedSyncTree[int]()
+
+		var wg sync.WaitGroup
+
+		for w := range writers {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				for i := range perGoroutine {
+					st.Insert(w*perGoroutine + i)
+				}
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				for i := range perGoroutine {
+					_ = st.Contains(i)
+					_ = st.Snapshot().Len()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		snapshot := st.Snapshot()
+
+		if st.Len() != writers*perGoroutine || !snapshot.IsValid() || snapshot.Len() != st.Len() {
+			t.Fail()
+		}
+
+		st.Insert(-1)
+
+		if snapshot.Contains(-1) {
+			t.Fail()
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package rbtree
+
+import (
+	"iter"
+	"slices"
+)
+
+// SortedView is an immutable sorted array of values returned by Finalize. It supports the searches of a tree
+// with binary search, while taking one slot per value instead of a node, which is compact and cache-friendly.
+type SortedView[T any] struct {
+	vals []T
+	cmp  func(T, T) int
+}
+
+// Finalize returns a read-only sorted view of the values of the tree for workloads, which build the tree once
+// and only query it afterwards. The tree is not modified, but it is not needed by the view, so dropping the tree
+// releases its nodes. Finalize takes O(n) time.
+func (rbt *RBTree[T]) Finalize() *SortedView[T] {
+	return &SortedView[T]{
+		vals: rbt.ToSlice(),
+		cmp:  rbt.cmp,
+	}
+}
+
+// Len returns the number of values in the view.
+func (view *SortedView[T]) Len() int {
+	return len(view.vals)
+}
+
+// Find returns the stored value equal to val and true if it exists in the view.
+func (view *SortedView[T]) Find(val T) (T, bool) {
+	i, ok := slices.BinarySearchFunc(view.vals, val, view.cmp)
+	if !ok {
+		var found T
+
+		return found, false
+	}
+
+	return view.vals[i], true
+}
+
+// Floor returns the biggest value less than or equal to val and true if there is such value.
+func (view *SortedView[T]) Floor(val T) (T, bool) {
+	i, ok := slices.BinarySearchFunc(view.vals, val, view.cmp)
+	if !ok {
+		i--
+	}
+
+	return view.Select(i)
+}
+
+// Ceiling returns the smallest value bigger than or equal to val and true if there is such value.
+func (view *SortedView[T]) Ceiling(val T) (T, bool) {
+	i, _ := slices.BinarySearchFunc(view.vals, val, view.cmp)
+
+	return view.Select(i)
+}
+
+// Select returns the k-th smallest value (0-indexed) and true if k is in the range [0, Len).
+func (view *SortedView[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= len(view.vals) {
+		var val T
+
+		return val, false
+	}
+
+	return view.vals[k], true
+}
+
+// Range returns an iterator over the values in the range [lo, hi] in ascending order.
+// If lo > hi, nothing is yielded.
+func (view *SortedView[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i, _ := slices.BinarySearchFunc(view.vals, lo, view.cmp)
+
+		for ; i < len(view.vals) && view.cmp(view.vals[i], hi) <= 0; i++ {
+			if !yield(view.vals[i]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package rbtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortedView(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SortedView: searches", func(t *testing.T) {
+		t.Parallel()
+
+		rbt := initRBTBefore()
+		view := rbt.Finalize()
+
+		if view.Len() != 7 || rbt.Count != 7 {
+			t.Fail()
+		}
+
+		if val, ok := view.Find(75); !ok || val != 75 {
+			t.Fail()
+		}
+
+		if _, ok := view.Find(65); ok {
+			t.Fail()
+		}
+
+		for _, val := range []int{0, 20, 55, 60, 65, 100, 200} {
+			floor, floorOK := view.Floor(val)
+			ceiling, ceilingOK := view.Ceiling(val)
+			floorNode, ceilingNode := rbt.floorNode(val), rbt.ceilingNode(val)
+
+			if floorOK != (floorNode != nil) || (floorOK && floor != floorNode.Val) {
+				t.Fail()
+			}
+
+			if ceilingOK != (ceilingNode != nil) || (ceilingOK && ceiling != ceilingNode.Val) {
+				t.Fail()
+			}
+		}
+	})
+
+	t.Run("SortedView: Select and Range", func(t *testing.T) {
+		t.Parallel()
+
+		view := initRBTBefore().Finalize()
+
+		if val, ok := view.Select(3); !ok || val != 70 {
+			t.Fail()
+		}
+
+		if _, ok := view.Select(7); ok {
+			t.Fail()
+		}
+
+		if !slices.Equal(slices.Collect(view.Range(55, 80)), []int{60, 70, 75, 80}) || len(slices.Collect(view.Range(80, 55))) != 0 {
+			t.Fail()
+		}
+	})
+
+	t.Run("SortedView: empty tree", func(t *testing.T) {
+		t.Parallel()
+
+		view := NewOrdered[int]().Finalize()
+
+		if _, ok := view.Floor(1); ok || view.Len() != 0 || len(slices.Collect(view.Range(0, 10))) != 0 {
+			t.Fail()
+		}
+	})
+}